@@ -0,0 +1,331 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkBlobReading(b *testing.B) {
+	for _, size := range blobSizes {
+		size := size
+
+		b.Run(size.name, func(b *testing.B) {
+			blobs := generateBlobs(size.size)
+
+			parquetFilename := fmt.Sprintf("blobrd_%s_testdata.parquet", size.name)
+
+			writeBlobFile(b, parquetFilename, blobs)
+
+			b.ResetTimer()
+
+			benchmarkBlobReading(b, parquetFilename, blobs)
+		})
+	}
+}
+
+// writeBlobFile writes the blobs into a single binary column with the
+// low-level writer.
+func writeBlobFile(b *testing.B, parquetFilename string, blobs [][]byte) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(blobWritingSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+	}
+	defer w.Close()
+
+	fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+	for _, blob := range blobs {
+		if err := fw.AddData(map[string]interface{}{"data": blob}); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+}
+
+// benchmarkBlobReading compares every decoded value against the original
+// blobs and reports the peak heap size, which shows whether a library holds
+// a whole column chunk or just a page of large values in memory.
+func benchmarkBlobReading(b *testing.B, parquetFilename string, blobs [][]byte) {
+	check := func(b *testing.B, row int, v []byte) {
+		if row >= len(blobs) {
+			b.Fatalf("read more than the %d rows that were written", len(blobs))
+		}
+		if !bytes.Equal(v, blobs[row]) {
+			b.Fatalf("row %d has %d different bytes, expected the %d written ones", row, len(v), len(blobs[row]))
+		}
+	}
+
+	checkCount := func(b *testing.B, rows int) {
+		if rows != len(blobs) {
+			b.Fatalf("read %d rows, expected %d", rows, len(blobs))
+		}
+	}
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				row := 0
+				for ; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					data, ok := values["data"].([]byte)
+					if !ok {
+						b.Fatalf("row %d: unexpected data %T", row, values["data"])
+					}
+					check(b, row, data)
+				}
+				checkCount(b, row)
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type record struct {
+			Data []byte `parquet:"data"`
+		}
+
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				row := 0
+				for ; r.Next(); row++ {
+					var rec record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, rec.Data)
+				}
+				checkCount(b, row)
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				row := 0
+				for ; r.Next(); row++ {
+					var rec myBlobRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, rec.Data)
+				}
+				checkCount(b, row)
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Data string `parquet:"name=data, type=BYTE_ARRAY"`
+		}
+
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 16
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					for _, r := range rec {
+						check(b, row, []byte(r.Data))
+						row++
+					}
+
+					num -= sliceSize
+				}
+				checkCount(b, row)
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				n := 0
+				for ; ; n++ {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					check(b, n, row[0].ByteArray())
+				}
+				checkCount(b, n)
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		values := make([]parquet3.ByteArray, 16)
+
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := file.OpenParquetFile(parquetFilename, false)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer r.Close()
+
+				row := 0
+
+				for rg := 0; rg < r.NumRowGroups(); rg++ {
+					col, ok := r.RowGroup(rg).Column(0).(*file.ByteArrayColumnChunkReader)
+					if !ok {
+						b.Fatalf("couldn't assert data column which is %T", r.RowGroup(rg).Column(0))
+					}
+
+					for col.HasNext() {
+						_, n, err := col.ReadBatch(int64(len(values)), values, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						for _, v := range values[:n] {
+							check(b, row, v)
+							row++
+						}
+					}
+				}
+				checkCount(b, row)
+			}()
+		}
+	})
+
+	// pqarrow holds the whole table in memory, so the peak heap size is at
+	// least the size of all blobs.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		defer trackPeakHeap(b)()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					values := chunk.(*array.Binary)
+					for j := 0; j < values.Len(); j++ {
+						check(b, row, values.Value(j))
+						row++
+					}
+				}
+				checkCount(b, row)
+			}()
+		}
+	})
+}
+
+type myBlobRecord struct {
+	Data []byte
+}
+
+func (r *myBlobRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	data, err := obj.GetField("data").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.Data = data
+	return nil
+}
@@ -0,0 +1,465 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"runtime/metrics"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const blobWritingSchema = `message blob {
+	required binary data;
+}`
+
+// blobSizes are the sizes of the binary values. Every scenario writes
+// blobTotalSize bytes of values in total.
+var blobSizes = []struct {
+	name string
+	size int
+}{
+	{name: "1KB", size: 1 << 10},
+	{name: "64KB", size: 64 << 10},
+	{name: "1MB", size: 1 << 20},
+}
+
+const blobTotalSize = 64 << 20
+
+// generateBlobs returns random, and thus incompressible, values of the
+// given size, like images or serialized and compressed blobs.
+func generateBlobs(size int) [][]byte {
+	blobs := make([][]byte, blobTotalSize/size)
+	for i := range blobs {
+		blobs[i] = make([]byte, size)
+		rand.Read(blobs[i])
+	}
+	return blobs
+}
+
+// trackPeakHeap samples the heap while a benchmark runs and returns a
+// function that stops sampling and reports the peak heap size above the
+// heap size when sampling started. Unlike runtime.ReadMemStats, reading
+// runtime/metrics doesn't stop the world, so sampling hardly affects the
+// measured times.
+func trackPeakHeap(b *testing.B) func() {
+	samples := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+
+	metrics.Read(samples)
+	base := samples[0].Value.Uint64()
+	peak := base
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		samples := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			metrics.Read(samples)
+			if v := samples[0].Value.Uint64(); v > peak {
+				peak = v
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+		b.ReportMetric(float64(peak-base), "peak-heap-bytes")
+	}
+}
+
+// blobPageSizes returns the number of pages of the first column of a file
+// and the size of the largest uncompressed page, which shows whether a
+// library splits large values sensibly across pages.
+func blobPageSizes(filename string) (numPages, maxPageSize int, err error) {
+	r, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer r.Close()
+
+	for rg := 0; rg < r.NumRowGroups(); rg++ {
+		pr, err := r.RowGroup(rg).GetColumnPageReader(0)
+		if err != nil {
+			return 0, 0, err
+		}
+		for pr.Next() {
+			numPages++
+			if size := len(pr.Page().Data()); size > maxPageSize {
+				maxPageSize = size
+			}
+		}
+		if err := pr.Err(); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return numPages, maxPageSize, nil
+}
+
+func verifyBlobFile(b *testing.B, filename string, blobs [][]byte) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(blobs) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(blobs))
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+
+		if v, _ := row["data"].([]byte); !bytes.Equal(v, blobs[i]) {
+			b.Fatalf("%s: row %d has %d different bytes, expected the %d written ones", filename, i, len(v), len(blobs[i]))
+		}
+	}
+
+	numPages, maxPageSize, err := blobPageSizes(filename)
+	if err != nil {
+		b.Fatalf("%s: reading pages failed: %v", filename, err)
+	}
+	b.ReportMetric(float64(numPages), "pages")
+	b.ReportMetric(float64(maxPageSize), "max-page-bytes")
+}
+
+func BenchmarkBlobWriting(b *testing.B) {
+	for _, size := range blobSizes {
+		size := size
+
+		b.Run(size.name, func(b *testing.B) {
+			blobs := generateBlobs(size.size)
+			b.ResetTimer()
+
+			benchmarkBlobWriting(b, blobs, fmt.Sprintf("blobwr_%s_", size.name))
+		})
+	}
+}
+
+func benchmarkBlobWriting(b *testing.B, blobs [][]byte, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(blobWritingSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+		type record struct {
+			Data []byte `parquet:"data"`
+		}
+
+		stop := trackPeakHeap(b)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, blob := range blobs {
+					if err = fw.Write(record{Data: blob}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		stop()
+		verifyBlobFile(b, parquetFilename, blobs)
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		stop := trackPeakHeap(b)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, blob := range blobs {
+					if err = fw.Write(blobRecord(blob)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		stop()
+		verifyBlobFile(b, parquetFilename, blobs)
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		stop := trackPeakHeap(b)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, blob := range blobs {
+					if err = fw.AddData(map[string]interface{}{"data": blob}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		stop()
+		verifyBlobFile(b, parquetFilename, blobs)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		type record struct {
+			Data string `parquet:"name=data, type=BYTE_ARRAY, encoding=PLAIN"`
+		}
+
+		stop := trackPeakHeap(b)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+				defer w.Close()
+
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, blob := range blobs {
+					if err = pw.Write(record{Data: string(blob)}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		stop()
+		verifyBlobFile(b, filename, blobs)
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				values := make([]parquet3.ByteArray, len(blobs))
+				for i, blob := range blobs {
+					values[i] = blob
+				}
+
+				stop := trackPeakHeap(b)
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer w.Close()
+
+						sc, err := schema.NewGroupNode("blob", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("data", parquet3.Repetitions.Required, parquet3.Types.ByteArray, 0, 0)),
+						}, 0)
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+						defer rg.Close()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						dataCol, ok := col.(*file.ByteArrayColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert data column which is %T", col)
+						}
+
+						if err := writeBatches(len(values), batchSize, func(start, end int) error {
+							_, err := dataCol.WriteBatch(values[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						dataCol.Close()
+					}()
+				}
+
+				b.StopTimer()
+				stop()
+				verifyBlobFile(b, filename, blobs)
+			})
+		}
+	})
+
+	// the record holds a copy of all blobs, which shows up in the peak
+	// heap size.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "data", Type: arrow.BinaryTypes.Binary}}, nil)
+
+		stop := trackPeakHeap(b)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.BinaryBuilder).AppendValues(blobs, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		stop()
+		verifyBlobFile(b, filename, blobs)
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio_parquet_go.parquet"
+
+		type record struct {
+			Data []byte `parquet:"data"`
+		}
+
+		stop := trackPeakHeap(b)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+				defer f.Close()
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, blob := range blobs {
+					if err := wr.Write(&record{Data: blob}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		stop()
+		verifyBlobFile(b, parquetFilename, blobs)
+	})
+}
+
+type blobRecord []byte
+
+func (r blobRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("data").SetByteArray(r)
+	return nil
+}
@@ -0,0 +1,255 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	parquet4 "github.com/segmentio/parquet-go"
+)
+
+// bloomLookups is the number of different lookups that the bloom filter
+// reading benchmarks cycle through.
+const bloomLookups = 1024
+
+// bloomLookup is a value that is looked up in one column of the bloom
+// filter file, and the number of rows that contain it.
+type bloomLookup struct {
+	id      int64
+	key     []byte
+	matches int
+}
+
+func BenchmarkBloomFilterReading(b *testing.B) {
+	data := generateBloomRecords(bloomRows)
+
+	parquetFilename := "bloomrd_testdata.parquet"
+
+	if err := writeSegmentioBloomFile(parquetFilename, data, true); err != nil {
+		b.Fatalf("Writing %s failed: %v", parquetFilename, err)
+	}
+
+	present := make([]bloomLookup, bloomLookups)
+	for i := range present {
+		rec := data[rand.Intn(len(data))]
+		present[i] = bloomLookup{id: rec.ID, key: []byte(rec.Key), matches: 1}
+	}
+
+	// absent values are drawn the same way as the values in the file and
+	// are then checked to really be absent.
+	ids := make(map[int64]bool, len(data))
+	keys := make(map[string]bool, len(data))
+	for _, rec := range data {
+		ids[rec.ID], keys[rec.Key] = true, true
+	}
+
+	absent := make([]bloomLookup, 0, bloomLookups)
+	for _, rec := range generateBloomRecords(bloomLookups * 2) {
+		if !ids[rec.ID] && !keys[rec.Key] && len(absent) < bloomLookups {
+			absent = append(absent, bloomLookup{id: rec.ID, key: []byte(rec.Key)})
+		}
+	}
+
+	b.ResetTimer()
+
+	for _, column := range []string{"id", "key"} {
+		column := column
+
+		b.Run(column, func(b *testing.B) {
+			b.Run("present", func(b *testing.B) {
+				benchmarkBloomFilterReading(b, parquetFilename, column, present)
+			})
+
+			b.Run("absent", func(b *testing.B) {
+				benchmarkBloomFilterReading(b, parquetFilename, column, absent)
+			})
+		})
+	}
+}
+
+// benchmarkBloomFilterReading counts the rows that contain the looked up
+// values in the given column. The file is only opened once, so that the
+// lookups themselves are measured.
+func benchmarkBloomFilterReading(b *testing.B, parquetFilename string, column string, lookups []bloomLookup) {
+	col := 0
+	if column == "key" {
+		col = 1
+	}
+
+	checkMatches := func(b *testing.B, lookup bloomLookup, matches int) {
+		if matches != lookup.matches {
+			b.Fatalf("found %s %d/%q in %d rows, expected %d", column, lookup.id, lookup.key, matches, lookup.matches)
+		}
+	}
+
+	report := func(b *testing.B, bytesRead int64, skipped int) {
+		b.ReportMetric(float64(bytesRead)/float64(b.N), "bytes-read/op")
+		b.ReportMetric(float64(skipped)/float64(b.N), "rowgroups-skipped/op")
+	}
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		b.Skip("parquet-go doesn't read bloom filters")
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		b.Skip("xitongsys doesn't read bloom filters")
+	})
+
+	// segmentioLookups checks the bloom filter of each row group before
+	// scanning its column chunk for the value, unless the file is scanned
+	// without using the bloom filters.
+	segmentioLookups := func(b *testing.B, useFilters bool) {
+		var (
+			bytesRead int64
+			skipped   int
+		)
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil {
+			b.Fatalf("Stat failed: %v", err)
+		}
+
+		pf, err := parquet4.OpenFile(f, stat.Size())
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		values := make([]parquet4.Value, 1024)
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			lookup := lookups[i%len(lookups)]
+
+			value := parquet4.ValueOf(lookup.id)
+			if col == 1 {
+				value = parquet4.ValueOf(lookup.key)
+			}
+
+			matches := 0
+			for g := 0; g < pf.NumRowGroups(); g++ {
+				chunk := pf.RowGroup(g).Column(col)
+
+				if useFilters {
+					if filter := chunk.BloomFilter(); filter != nil {
+						ok, err := filter.Check(value)
+						if err != nil {
+							b.Fatalf("Checking bloom filter failed: %v", err)
+						}
+						if !ok {
+							skipped++
+							continue
+						}
+					}
+				}
+
+				pages := chunk.Pages()
+				for {
+					page, err := pages.ReadPage()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadPage failed: %v", err)
+					}
+
+					for vr := page.Values(); ; {
+						n, err := vr.ReadValues(values)
+						for _, v := range values[:n] {
+							if (col == 0 && v.Int64() == lookup.id) || (col == 1 && bytes.Equal(v.ByteArray(), lookup.key)) {
+								matches++
+							}
+						}
+						if err != nil {
+							if errors.Is(err, io.EOF) {
+								break
+							}
+							b.Fatalf("ReadValues failed: %v", err)
+						}
+					}
+				}
+			}
+			checkMatches(b, lookup, matches)
+		}
+
+		report(b, bytesRead, skipped)
+	}
+
+	b.Run("segmentio", func(b *testing.B) {
+		segmentioLookups(b, true)
+	})
+
+	b.Run("segmentio_scan", func(b *testing.B) {
+		segmentioLookups(b, false)
+	})
+
+	// arrow doesn't read bloom filters, so it scans the column.
+	b.Run("apache_arrow", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		r, err := file.NewParquetReader(f)
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		int64Values := make([]int64, 1024)
+		byteArrayValues := make([]parquet3.ByteArray, 1024)
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			lookup := lookups[i%len(lookups)]
+
+			matches := 0
+			for rg := 0; rg < r.NumRowGroups(); rg++ {
+				for cr := r.RowGroup(rg).Column(col); cr.HasNext(); {
+					switch cr := cr.(type) {
+					case *file.Int64ColumnChunkReader:
+						_, n, err := cr.ReadBatch(int64(len(int64Values)), int64Values, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						for _, v := range int64Values[:n] {
+							if v == lookup.id {
+								matches++
+							}
+						}
+					case *file.ByteArrayColumnChunkReader:
+						_, n, err := cr.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						for _, v := range byteArrayValues[:n] {
+							if bytes.Equal(v, lookup.key) {
+								matches++
+							}
+						}
+					default:
+						b.Fatalf("unexpected column reader %T", cr)
+					}
+				}
+			}
+			checkMatches(b, lookup, matches)
+		}
+
+		report(b, bytesRead, 0)
+	})
+}
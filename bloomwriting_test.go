@@ -0,0 +1,203 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	goparquet "github.com/fraugster/parquet-go"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+)
+
+// The bloom filter files have bloomRows rows of unique ids and keys, in
+// row groups of bloomRowGroupSize rows that each get their own filters.
+const (
+	bloomRows         = 1000000
+	bloomRowGroupSize = 100000
+)
+
+type bloomRecord struct {
+	ID  int64  `parquet:"id"`
+	Key string `parquet:"key"`
+}
+
+// generateBloomRecords returns n records with unique random ids and keys.
+func generateBloomRecords(n int) []bloomRecord {
+	ids := make(map[int64]bool, n)
+	keys := make(map[string]bool, n)
+
+	data := make([]bloomRecord, 0, n)
+	for len(data) < n {
+		rec := bloomRecord{ID: rand.Int63(), Key: fmt.Sprintf("%016x", rand.Uint64())}
+		if ids[rec.ID] || keys[rec.Key] {
+			continue
+		}
+		ids[rec.ID], keys[rec.Key] = true, true
+		data = append(data, rec)
+	}
+	return data
+}
+
+// writeSegmentioBloomFile writes the records with segmentio, optionally
+// with split-block bloom filters for both columns.
+func writeSegmentioBloomFile(filename string, data []bloomRecord, bloomFilters bool) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	options := []parquet4.WriterOption{
+		parquet4.SchemaOf(new(bloomRecord)),
+		parquet4.Compression(&snappy.Codec{}),
+	}
+	if bloomFilters {
+		options = append(options, parquet4.BloomFilters(
+			parquet4.SplitBlockFilter("id"),
+			parquet4.SplitBlockFilter("key"),
+		))
+	}
+
+	wr := parquet4.NewWriter(f, options...)
+
+	for i := range data {
+		if err := wr.Write(&data[i]); err != nil {
+			return err
+		}
+		if (i+1)%bloomRowGroupSize == 0 {
+			if err := wr.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return wr.Close()
+}
+
+// bloomFilterSize returns the total size of the bloom filters in a file.
+func bloomFilterSize(filename string) (int64, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	pf, err := parquet4.OpenFile(f, stat.Size())
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for g := 0; g < pf.NumRowGroups(); g++ {
+		rg := pf.RowGroup(g)
+		for c := 0; c < rg.NumColumns(); c++ {
+			if filter := rg.Column(c).BloomFilter(); filter != nil {
+				size += filter.Size()
+			}
+		}
+	}
+	return size, nil
+}
+
+func verifyBloomFile(b *testing.B, filename string, data []bloomRecord) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(data) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(data))
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+
+		key, _ := row["key"].([]byte)
+		if row["id"] != data[i].ID || string(key) != data[i].Key {
+			b.Fatalf("%s: row %d is %v/%q, expected %d/%q", filename, i, row["id"], key, data[i].ID, data[i].Key)
+		}
+	}
+}
+
+func BenchmarkBloomFilterWriting(b *testing.B) {
+	data := generateBloomRecords(bloomRows)
+
+	prefix := "bloomwr_"
+
+	// report reports the file size and how much of it the bloom filters
+	// take up.
+	report := func(b *testing.B, filename string) {
+		stat, err := os.Stat(filename)
+		if err != nil {
+			b.Fatalf("Stat failed: %v", err)
+		}
+		filterSize, err := bloomFilterSize(filename)
+		if err != nil {
+			b.Fatalf("%s: reading bloom filters failed: %v", filename, err)
+		}
+		b.ReportMetric(float64(stat.Size()), "file-bytes")
+		b.ReportMetric(float64(filterSize), "filter-bytes")
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		b.Skip("parquet-go doesn't write bloom filters")
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		b.Skip("xitongsys doesn't write bloom filters")
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		b.Skip("arrow doesn't write bloom filters")
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		filename := prefix + "segmentio_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			if err := writeSegmentioBloomFile(filename, data, false); err != nil {
+				b.Fatalf("Writing %s failed: %v", filename, err)
+			}
+		}
+
+		b.StopTimer()
+		verifyBloomFile(b, filename, data)
+		report(b, filename)
+	})
+
+	b.Run("segmentio_parquet_go_bloom_filters", func(b *testing.B) {
+		filename := prefix + "segmentio_parquet_go_bloom_filters.parquet"
+
+		for n := 0; n < b.N; n++ {
+			if err := writeSegmentioBloomFile(filename, data, true); err != nil {
+				b.Fatalf("Writing %s failed: %v", filename, err)
+			}
+		}
+
+		b.StopTimer()
+		verifyBloomFile(b, filename, data)
+		report(b, filename)
+	})
+}
@@ -0,0 +1,335 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// boolReadingEncodings are the encodings of the boolean columns that are
+// read. Apache arrow can't decode RLE encoded boolean pages at all, and
+// xitongsys fails on the ones written by parquet-go.
+var boolReadingEncodings = []struct {
+	name     string
+	encoding parquet.Encoding
+}{
+	{name: "plain", encoding: parquet.Encoding_PLAIN},
+	{name: "rle", encoding: parquet.Encoding_RLE},
+}
+
+func BenchmarkBoolReading(b *testing.B) {
+	numRecords := 1000000
+
+	for _, gen := range boolGenerators {
+		gen := gen
+
+		b.Run(gen.name, func(b *testing.B) {
+			data := gen.generate(numRecords)
+
+			for _, enc := range boolReadingEncodings {
+				enc := enc
+
+				b.Run(enc.name, func(b *testing.B) {
+					benchmarkBoolReading(b, data, "bool_"+gen.name+"_"+enc.name+"_", enc.encoding)
+				})
+			}
+		})
+	}
+}
+
+// benchmarkBoolReading reads a boolean column that parquet-go wrote with
+// the given encoding.
+func benchmarkBoolReading(b *testing.B, data []bool, prefix string, encoding parquet.Encoding) {
+	parquetFilename := prefix + "testdata.parquet"
+
+	w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+	}
+
+	fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+	boolStore, err := goparquet.NewBooleanStore(encoding, &goparquet.ColumnParameters{})
+	if err != nil {
+		b.Fatalf("NewBooleanStore failed: %v", err)
+	}
+	if err := fw.AddColumn("foo", goparquet.NewDataColumn(boolStore, parquet.FieldRepetitionType_REQUIRED)); err != nil {
+		b.Fatalf("AddColumn failed: %v", err)
+	}
+
+	for _, v := range data {
+		if err = fw.AddData(map[string]interface{}{"foo": v}); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+	w.Close()
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for {
+					_, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Foo bool `parquet:"foo"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec reflectRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec myBoolRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		if encoding == parquet.Encoding_RLE {
+			b.Skip("xitongsys can't read RLE encoded boolean pages written by parquet-go")
+		}
+
+		type record struct {
+			Foo bool `parquet:"name=foo, type=BOOLEAN"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		type record struct {
+			Foo bool `parquet:"foo"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				for {
+					var rec record
+					if err := r.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		if encoding == parquet.Encoding_RLE {
+			b.Skip("arrow can't decode RLE encoded boolean pages")
+		}
+
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]bool, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.BooleanColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert foo column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								if _, _, err := col.ReadBatch(int64(len(values)), values, nil, nil); err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		if encoding == parquet.Encoding_RLE {
+			b.Skip("arrow can't decode RLE encoded boolean pages")
+		}
+
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				if batchSize%8 != 0 {
+					b.Skip("arrow v8's boolean record reader returns wrong values if the batch size isn't a multiple of 8")
+				}
+
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						values := rec.Column(0).(*array.Boolean)
+						for j := 0; j < values.Len(); j++ {
+							if v := values.Value(j); v != data[row] {
+								b.Fatalf("row %d is %t, expected %t", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		if encoding == parquet.Encoding_RLE {
+			b.Skip("arrow can't decode RLE encoded boolean pages")
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					values := chunk.(*array.Boolean)
+					for j := 0; j < values.Len(); j++ {
+						if v := values.Value(j); v != data[row] {
+							b.Fatalf("row %d is %t, expected %t", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myBoolRecord struct {
+	Foo bool `parquet:"foo"`
+}
+
+func (r *myBoolRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	v, err := obj.GetField("foo").Bool()
+	if err != nil {
+		return err
+	}
+	r.Foo = v
+	return nil
+}
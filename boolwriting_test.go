@@ -0,0 +1,411 @@
+package benchmark_test
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// boolGenerators produce boolean columns with different distributions, which
+// lead to very different bit-packed and RLE encoded representations.
+var boolGenerators = []struct {
+	name     string
+	generate func(n int) []bool
+}{
+	{name: "random", generate: generateRandomBools},
+	{name: "mostly_true", generate: generateMostlyTrueBools},
+	{name: "long_runs", generate: generateLongRunBools},
+}
+
+func generateRandomBools(n int) []bool {
+	data := make([]bool, n)
+	for i := range data {
+		data[i] = rand.Intn(2) == 1
+	}
+	return data
+}
+
+func generateMostlyTrueBools(n int) []bool {
+	data := make([]bool, n)
+	for i := range data {
+		data[i] = rand.Intn(100) != 0
+	}
+	return data
+}
+
+// generateLongRunBools returns alternating runs of equal values that are
+// several thousand values long.
+func generateLongRunBools(n int) []bool {
+	data := make([]bool, n)
+	value := false
+	for i := 0; i < n; {
+		runLength := 1000 + rand.Intn(10000)
+		for j := 0; j < runLength && i < n; j++ {
+			data[i] = value
+			i++
+		}
+		value = !value
+	}
+	return data
+}
+
+func BenchmarkBoolWriting(b *testing.B) {
+	numRecords := 1000000
+
+	for _, gen := range boolGenerators {
+		gen := gen
+
+		b.Run(gen.name, func(b *testing.B) {
+			prefix := "boolwr_" + gen.name + "_"
+
+			data := gen.generate(numRecords)
+
+			benchmarkBoolWriting(b, data, prefix)
+		})
+	}
+}
+
+const boolWritingSchema = `message test {
+	required boolean foo;
+}`
+
+func benchmarkBoolWriting(b *testing.B, data []bool, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(boolWritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Foo bool `parquet:"foo"`
+				}
+
+				for _, v := range data {
+					stu := record{
+						Foo: v,
+					}
+					if err = fw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(boolWritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, v := range data {
+					r := boolRecord(v)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel_plain", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "parquet_go_lowlevel_plain.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+				boolStore, err := goparquet.NewBooleanStore(parquet.Encoding_PLAIN, &goparquet.ColumnParameters{})
+				if err != nil {
+					b.Fatalf("NewBooleanStore failed: %v", err)
+				}
+				fw.AddColumn("foo", goparquet.NewDataColumn(boolStore, parquet.FieldRepetitionType_REQUIRED))
+
+				for _, v := range data {
+					stu := map[string]interface{}{
+						"foo": v,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel_rle", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "parquet_go_lowlevel_rle.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+				boolStore, err := goparquet.NewBooleanStore(parquet.Encoding_RLE, &goparquet.ColumnParameters{})
+				if err != nil {
+					b.Fatalf("NewBooleanStore failed: %v", err)
+				}
+				fw.AddColumn("foo", goparquet.NewDataColumn(boolStore, parquet.FieldRepetitionType_REQUIRED))
+
+				for _, v := range data {
+					stu := map[string]interface{}{
+						"foo": v,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plain", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plain.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo bool `parquet:"name=foo, type=BOOLEAN, encoding=PLAIN"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, v := range data {
+					stu := record{
+						Foo: v,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_rle", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_rle.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo bool `parquet:"name=foo, type=BOOLEAN, encoding=RLE"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, v := range data {
+					stu := record{
+						Foo: v,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("foo", parquet3.Repetitions.Required, parquet3.Types.Boolean, 0, 0)),
+						}, 0)
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						fooCol, ok := col.(*file.BooleanColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert foo column which is %T", col)
+						}
+
+						if err := writeBatches(len(data), batchSize, func(start, end int) error {
+							_, err := fooCol.WriteBatch(data[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						fooCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "foo", Type: arrow.FixedWidthTypes.Boolean}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.BooleanBuilder).AppendValues(data, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		type record struct {
+			Foo bool `parquet:"foo"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, v := range data {
+					if err := wr.Write(&record{
+						Foo: v,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+}
+
+type boolRecord bool
+
+func (r boolRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("foo").SetBool(bool(r))
+	return nil
+}
@@ -0,0 +1,502 @@
+package benchmark_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// concurrentRows rows are written to the concurrent reading file, in row
+// groups of concurrentRowGroupSize rows that are distributed among the
+// reading goroutines.
+const (
+	concurrentRows         = 1000000
+	concurrentRowGroupSize = 10000
+)
+
+// writeConcurrentFile writes the records with apache arrow, in row groups
+// of concurrentRowGroupSize rows.
+func writeConcurrentFile(filename string, data []parallelRecord) error {
+	w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	sc, err := schema.NewGroupNode("parallel", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("name", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+		schema.MustPrimitive(schema.NewPrimitiveNode("value", parquet3.Repetitions.Required, parquet3.Types.Double, 0, 0)),
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+
+	for start := 0; start < len(data); start += concurrentRowGroupSize {
+		rows := data[start:]
+		if len(rows) > concurrentRowGroupSize {
+			rows = rows[:concurrentRowGroupSize]
+		}
+
+		ids := make([]int64, len(rows))
+		names := make([]parquet3.ByteArray, len(rows))
+		values := make([]float64, len(rows))
+		for i, rec := range rows {
+			ids[i], names[i], values[i] = rec.ID, parquet3.ByteArray(rec.Name), rec.Value
+		}
+
+		rg := pw.AppendRowGroup()
+
+		for c := 0; c < sc.NumFields(); c++ {
+			col, err := rg.NextColumn()
+			if err != nil {
+				return err
+			}
+
+			switch col := col.(type) {
+			case *file.Int64ColumnChunkWriter:
+				_, err = col.WriteBatch(ids, nil, nil)
+			case *file.ByteArrayColumnChunkWriter:
+				_, err = col.WriteBatch(names, nil, nil)
+			case *file.Float64ColumnChunkWriter:
+				_, err = col.WriteBatch(values, nil, nil)
+			default:
+				return fmt.Errorf("unexpected column writer %T", col)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := col.Close(); err != nil {
+				return err
+			}
+		}
+
+		if err := rg.Close(); err != nil {
+			return err
+		}
+	}
+
+	return pw.Close()
+}
+
+// readRowGroupsConcurrently distributes the row groups of a file among
+// workers goroutines. Each of them creates its own read function with
+// newReader, as not all readers may be shared between goroutines, and
+// calls it for the row groups it gets. The returned done function is
+// called when a goroutine has no more row groups to read.
+func readRowGroupsConcurrently(numRowGroups, workers int, newReader func() (read func(rg int) error, done func(), err error)) error {
+	rowGroups := make(chan int, numRowGroups)
+	for rg := 0; rg < numRowGroups; rg++ {
+		rowGroups <- rg
+	}
+	close(rowGroups)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := func() error {
+				read, done, err := newReader()
+				if err != nil {
+					return err
+				}
+				defer done()
+
+				for rg := range rowGroups {
+					if err := read(rg); err != nil {
+						return fmt.Errorf("row group %d: %w", rg, err)
+					}
+				}
+				return nil
+			}()
+			if err != nil {
+				errOnce.Do(func() { firstErr = err })
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// BenchmarkConcurrentReading reads all rows of a single file, first from
+// one goroutine and then from as many goroutines as GOMAXPROCS allows, and
+// reports the speedup of the latter. The row groups, or columns where a
+// library only parallelizes those, are read concurrently using each
+// library's API. Run it with -race to check the libraries for data races.
+func BenchmarkConcurrentReading(b *testing.B) {
+	data := generateParallelRecords(concurrentRows)
+
+	parquetFilename := "concurrentrd_testdata.parquet"
+
+	if err := writeConcurrentFile(parquetFilename, data); err != nil {
+		b.Fatalf("Writing %s failed: %v", parquetFilename, err)
+	}
+
+	b.ResetTimer()
+
+	benchmarkConcurrentReading(b, parquetFilename, data)
+}
+
+func benchmarkConcurrentReading(b *testing.B, parquetFilename string, data []parallelRecord) {
+	numRowGroups := (len(data) + concurrentRowGroupSize - 1) / concurrentRowGroupSize
+
+	// check is called concurrently, so it returns an error instead of
+	// failing the benchmark.
+	check := func(row int, id int64, name []byte, value float64) error {
+		if row >= len(data) {
+			return fmt.Errorf("read more than the %d rows that were written", len(data))
+		}
+		if rec := data[row]; id != rec.ID || string(name) != rec.Name || value != rec.Value {
+			return fmt.Errorf("row %d is %d/%q/%f, expected %d/%q/%f", row, id, name, value, rec.ID, rec.Name, rec.Value)
+		}
+		return nil
+	}
+
+	checkCount := func(rg, rows int) error {
+		if rows != concurrentRowGroupSize {
+			return fmt.Errorf("read %d rows from row group %d, expected %d", rows, rg, concurrentRowGroupSize)
+		}
+		return nil
+	}
+
+	// run benchmarks read with a single goroutine and with GOMAXPROCS
+	// goroutines. The speedup is only reported if both ran.
+	run := func(b *testing.B, read func(workers int) error) {
+		var sequential time.Duration
+
+		b.Run("sequential", func(b *testing.B) {
+			start := time.Now()
+
+			for i := 0; i < b.N; i++ {
+				if err := read(1); err != nil {
+					b.Fatalf("Reading failed: %v", err)
+				}
+			}
+
+			sequential = time.Since(start) / time.Duration(b.N)
+		})
+
+		b.Run("concurrent", func(b *testing.B) {
+			start := time.Now()
+
+			for i := 0; i < b.N; i++ {
+				if err := read(runtime.GOMAXPROCS(0)); err != nil {
+					b.Fatalf("Reading failed: %v", err)
+				}
+			}
+
+			concurrent := time.Since(start) / time.Duration(b.N)
+			if sequential > 0 {
+				b.ReportMetric(float64(sequential)/float64(concurrent), "speedup")
+			}
+		})
+	}
+
+	// floor reads through the low-level reader, so it isn't benchmarked
+	// separately.
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		f, err := os.Open(parquetFilename)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+
+		meta, err := goparquet.ReadFileMetaData(f, false)
+		f.Close()
+		if err != nil {
+			b.Fatalf("Reading file metadata failed: %v", err)
+		}
+
+		// a file reader reads one row group at a time, so each goroutine
+		// opens its own, sharing the metadata.
+		newReader := func() (func(rg int) error, func(), error) {
+			f, err := os.Open(parquetFilename)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			r, err := goparquet.NewFileReaderWithMetaData(f, meta)
+			if err != nil {
+				f.Close()
+				return nil, nil, err
+			}
+
+			read := func(rg int) error {
+				// SeekToRowGroup(i) loads the row group before i.
+				if err := r.SeekToRowGroup(rg + 1); err != nil {
+					return err
+				}
+
+				rows := int(meta.RowGroups[rg].NumRows)
+				for i := 0; i < rows; i++ {
+					values, err := r.NextRow()
+					if err != nil {
+						return err
+					}
+					name, _ := values["name"].([]byte)
+					id, _ := values["id"].(int64)
+					value, _ := values["value"].(float64)
+					if err := check(rg*concurrentRowGroupSize+i, id, name, value); err != nil {
+						return err
+					}
+				}
+				return checkCount(rg, rows)
+			}
+
+			return read, func() { f.Close() }, nil
+		}
+
+		run(b, func(workers int) error {
+			return readRowGroupsConcurrently(numRowGroups, workers, newReader)
+		})
+	})
+
+	// xitongsys reads the columns concurrently, with as many goroutines as
+	// its parallelism parameter, so there's no gain beyond the number of
+	// columns.
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			ID    int64   `parquet:"name=id, type=INT64"`
+			Name  string  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+			Value float64 `parquet:"name=value, type=DOUBLE"`
+		}
+
+		run(b, func(workers int) error {
+			fr, err := local.NewLocalFileReader(parquetFilename)
+			if err != nil {
+				return err
+			}
+			defer fr.Close()
+
+			pr, err := reader.NewParquetReader(fr, new(record), int64(workers))
+			if err != nil {
+				return err
+			}
+			defer pr.ReadStop()
+
+			num := int(pr.GetNumRows())
+			row := 0
+
+			for num > 0 {
+				sliceSize := concurrentRowGroupSize
+				if num < sliceSize {
+					sliceSize = num
+				}
+				rec := make([]record, sliceSize)
+				if err := pr.Read(&rec); err != nil {
+					return err
+				}
+				for _, r := range rec {
+					if err := check(row, r.ID, []byte(r.Name), r.Value); err != nil {
+						return err
+					}
+					row++
+				}
+
+				num -= sliceSize
+			}
+
+			if row != len(data) {
+				return fmt.Errorf("read %d rows, expected %d", row, len(data))
+			}
+			return nil
+		})
+	})
+
+	// the file is opened once and its row groups are read from all
+	// goroutines.
+	b.Run("segmentio", func(b *testing.B) {
+		f, err := os.Open(parquetFilename)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil {
+			b.Fatalf("Stat failed: %v", err)
+		}
+
+		pf, err := parquet4.OpenFile(f, stat.Size())
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		newReader := func() (func(rg int) error, func(), error) {
+			var row parquet4.Row
+
+			read := func(rg int) error {
+				rows := pf.RowGroup(rg).Rows()
+
+				n := 0
+				for ; ; n++ {
+					var err error
+					row, err = rows.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						return err
+					}
+					if err := check(rg*concurrentRowGroupSize+n, row[0].Int64(), row[1].ByteArray(), row[2].Double()); err != nil {
+						return err
+					}
+				}
+				return checkCount(rg, n)
+			}
+
+			return read, func() {}, nil
+		}
+
+		run(b, func(workers int) error {
+			return readRowGroupsConcurrently(numRowGroups, workers, newReader)
+		})
+	})
+
+	// the file is opened once and its row groups are read from all
+	// goroutines.
+	b.Run("apache_arrow", func(b *testing.B) {
+		r, err := file.OpenParquetFile(parquetFilename, false)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer r.Close()
+
+		newReader := func() (func(rg int) error, func(), error) {
+			ids := make([]int64, concurrentRowGroupSize)
+			names := make([]parquet3.ByteArray, concurrentRowGroupSize)
+			values := make([]float64, concurrentRowGroupSize)
+
+			read := func(rg int) error {
+				rgr := r.RowGroup(rg)
+
+				idReader, ok := rgr.Column(0).(*file.Int64ColumnChunkReader)
+				if !ok {
+					return fmt.Errorf("unexpected id column reader %T", rgr.Column(0))
+				}
+				nameReader, ok := rgr.Column(1).(*file.ByteArrayColumnChunkReader)
+				if !ok {
+					return fmt.Errorf("unexpected name column reader %T", rgr.Column(1))
+				}
+				valueReader, ok := rgr.Column(2).(*file.Float64ColumnChunkReader)
+				if !ok {
+					return fmt.Errorf("unexpected value column reader %T", rgr.Column(2))
+				}
+
+				// the buffers hold a whole row group, so a single batch
+				// reads all values of a column chunk.
+				_, rows, err := idReader.ReadBatch(int64(len(ids)), ids, nil, nil)
+				if err != nil {
+					return err
+				}
+				if _, _, err := nameReader.ReadBatch(int64(rows), names, nil, nil); err != nil {
+					return err
+				}
+				if _, _, err := valueReader.ReadBatch(int64(rows), values, nil, nil); err != nil {
+					return err
+				}
+
+				for i := 0; i < rows; i++ {
+					if err := check(rg*concurrentRowGroupSize+i, ids[i], names[i], values[i]); err != nil {
+						return err
+					}
+				}
+				return checkCount(rg, rows)
+			}
+
+			return read, func() {}, nil
+		}
+
+		run(b, func(workers int) error {
+			return readRowGroupsConcurrently(numRowGroups, workers, newReader)
+		})
+	})
+
+	// every goroutine reads row groups into tables with a pqarrow reader
+	// of its own on top of the shared file.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		r, err := file.OpenParquetFile(parquetFilename, false)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer r.Close()
+
+		newReader := func() (func(rg int) error, func(), error) {
+			fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			read := func(rg int) error {
+				tbl, err := fr.RowGroup(rg).ReadTable(context.Background(), []int{0, 1, 2})
+				if err != nil {
+					return err
+				}
+				defer tbl.Release()
+
+				tr := array.NewTableReader(tbl, -1)
+				defer tr.Release()
+
+				rows := 0
+				for tr.Next() {
+					rec := tr.Record()
+					ids, ok := rec.Column(0).(*array.Int64)
+					if !ok {
+						return fmt.Errorf("unexpected id array %T", rec.Column(0))
+					}
+					names, ok := rec.Column(1).(*array.String)
+					if !ok {
+						return fmt.Errorf("unexpected name array %T", rec.Column(1))
+					}
+					values, ok := rec.Column(2).(*array.Float64)
+					if !ok {
+						return fmt.Errorf("unexpected value array %T", rec.Column(2))
+					}
+
+					for i := 0; i < int(rec.NumRows()); i++ {
+						if err := check(rg*concurrentRowGroupSize+rows, ids.Value(i), []byte(names.Value(i)), values.Value(i)); err != nil {
+							return err
+						}
+						rows++
+					}
+				}
+				return checkCount(rg, rows)
+			}
+
+			return read, func() {}, nil
+		}
+
+		run(b, func(workers int) error {
+			return readRowGroupsConcurrently(numRowGroups, workers, newReader)
+		})
+	})
+}
@@ -0,0 +1,535 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkDateTimeReading(b *testing.B) {
+	numRecords := 1000000
+
+	for _, dt := range dateTimeTypes {
+		dt := dt
+
+		b.Run(dt.name, func(b *testing.B) {
+			data := make([]interface{}, numRecords)
+			for i, t := range dt.generate(numRecords) {
+				data[i] = dt.raw(t)
+			}
+			b.ResetTimer()
+
+			benchmarkDateTimeReading(b, data, dt, "datetime_"+dt.name+"_")
+		})
+	}
+}
+
+// benchmarkDateTimeReading compares every decoded value against the
+// original data, so the INT96 variant also shows which readers can decode
+// legacy timestamps correctly.
+func benchmarkDateTimeReading(b *testing.B, data []interface{}, dt dateTimeType, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(dt.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, v := range data {
+		if err = fw.Write(dateTimeRecord{v: v}); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	// The expected values are unpacked into a slice of the column's
+	// physical type, so that the checks don't box every value that is read.
+	var (
+		int32s []int32
+		int64s []int64
+		int96s [][12]byte
+	)
+	switch dt.physical() {
+	case parquet.Type_INT32:
+		int32s = make([]int32, len(data))
+		for i, v := range data {
+			int32s[i] = v.(int32)
+		}
+	case parquet.Type_INT64:
+		int64s = make([]int64, len(data))
+		for i, v := range data {
+			int64s[i] = v.(int64)
+		}
+	default:
+		int96s = make([][12]byte, len(data))
+		for i, v := range data {
+			int96s[i] = v.([12]byte)
+		}
+	}
+
+	checkRow := func(b *testing.B, row int, physical parquet.Type) {
+		if row >= len(data) {
+			b.Fatalf("read more than the %d rows that were written", len(data))
+		}
+		if physical != dt.physical() {
+			b.Fatalf("row %d is %s, expected %s", row, physical, dt.physical())
+		}
+	}
+
+	checkInt32 := func(b *testing.B, row int, v int32) {
+		checkRow(b, row, parquet.Type_INT32)
+		if v != int32s[row] {
+			b.Fatalf("row %d is %d, expected %d", row, v, int32s[row])
+		}
+	}
+
+	checkInt64 := func(b *testing.B, row int, v int64) {
+		checkRow(b, row, parquet.Type_INT64)
+		if v != int64s[row] {
+			b.Fatalf("row %d is %d, expected %d", row, v, int64s[row])
+		}
+	}
+
+	checkInt96 := func(b *testing.B, row int, v [12]byte) {
+		checkRow(b, row, parquet.Type_INT96)
+		if v != int96s[row] {
+			b.Fatalf("row %d is %v, expected %v", row, v, int96s[row])
+		}
+	}
+
+	// checkNanosOfDay checks a time of day given in nanoseconds.
+	checkNanosOfDay := func(b *testing.B, row int, nanos int64) {
+		if v := nanos / int64(dt.unit.duration); dt.physical() == parquet.Type_INT32 {
+			checkInt32(b, row, int32(v))
+		} else {
+			checkInt64(b, row, v)
+		}
+	}
+
+	// checkTime checks a value that was read as a time.Time.
+	checkTime := func(b *testing.B, row int, t time.Time) {
+		switch dt.kind {
+		case kindDate:
+			checkInt32(b, row, int32(t.Unix()/(24*60*60)))
+		case kindTime:
+			checkNanosOfDay(b, row, nanosOfDay(t))
+		default:
+			checkInt96(b, row, goparquet.TimeToInt96(t))
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for row := 0; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					switch v := values["value"].(type) {
+					case int32:
+						checkInt32(b, row, v)
+					case int64:
+						checkInt64(b, row, v)
+					case [12]byte:
+						checkInt96(b, row, v)
+					default:
+						b.Fatalf("row %d: unexpected value of type %T", row, v)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Value time.Time `parquet:"value"`
+		}
+
+		type timeReflectRecord struct {
+			Value floor.Time `parquet:"value"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					if dt.kind == kindTime {
+						var rec timeReflectRecord
+						if err := r.Scan(&rec); err != nil {
+							b.Fatalf("Scan failed: %v", err)
+						}
+						checkNanosOfDay(b, row, rec.Value.Nanoseconds())
+					} else {
+						var rec reflectRecord
+						if err := r.Scan(&rec); err != nil {
+							b.Fatalf("Scan failed: %v", err)
+						}
+						checkTime(b, row, rec.Value)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					rec := myDateTimeRecord{physical: dt.physical()}
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					switch rec.physical {
+					case parquet.Type_INT32:
+						checkInt32(b, row, rec.Int32)
+					case parquet.Type_INT64:
+						checkInt64(b, row, rec.Int64)
+					default:
+						checkInt96(b, row, rec.Int96)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type int32Record struct {
+			Value int32
+		}
+
+		type int64Record struct {
+			Value int64
+		}
+
+		type int96Record struct {
+			Value string
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, dt.xitongsysSchema(), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+
+					switch dt.physical() {
+					case parquet.Type_INT32:
+						rec := make([]int32Record, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								checkInt32(b, row, r.Value)
+								row++
+							}
+						}
+					case parquet.Type_INT64:
+						rec := make([]int64Record, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								checkInt64(b, row, r.Value)
+								row++
+							}
+						}
+					default:
+						rec := make([]int96Record, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								var v [12]byte
+								copy(v[:], r.Value)
+								checkInt96(b, row, v)
+								row++
+							}
+						}
+					}
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		if dt.kind == kindDate {
+			b.Skip("segmentio decodes DATE columns as if they were INT64 and returns wrong values")
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				for n := 0; ; n++ {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					switch dt.physical() {
+					case parquet.Type_INT32:
+						checkInt32(b, n, row[0].Int32())
+					case parquet.Type_INT64:
+						checkInt64(b, n, row[0].Int64())
+					default:
+						checkInt96(b, n, int96Bytes(row[0].Int96()))
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				int32Values := make([]int32, batchSize)
+				int64Values := make([]int64, batchSize)
+				int96Values := make([]parquet3.Int96, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						row := 0
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							switch col := r.RowGroup(rg).Column(0).(type) {
+							case *file.Int32ColumnChunkReader:
+								for col.HasNext() {
+									_, n, err := col.ReadBatch(int64(len(int32Values)), int32Values, nil, nil)
+									if err != nil {
+										b.Fatalf("ReadBatch failed: %v", err)
+									}
+									for _, v := range int32Values[:n] {
+										checkInt32(b, row, v)
+										row++
+									}
+								}
+							case *file.Int64ColumnChunkReader:
+								for col.HasNext() {
+									_, n, err := col.ReadBatch(int64(len(int64Values)), int64Values, nil, nil)
+									if err != nil {
+										b.Fatalf("ReadBatch failed: %v", err)
+									}
+									for _, v := range int64Values[:n] {
+										checkInt64(b, row, v)
+										row++
+									}
+								}
+							case *file.Int96ColumnChunkReader:
+								for col.HasNext() {
+									_, n, err := col.ReadBatch(int64(len(int96Values)), int96Values, nil, nil)
+									if err != nil {
+										b.Fatalf("ReadBatch failed: %v", err)
+									}
+									for _, v := range int96Values[:n] {
+										checkInt96(b, row, [12]byte(v))
+										row++
+									}
+								}
+							default:
+								b.Fatalf("unexpected value column %T", col)
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	// Both pqarrow variants convert INT96 values into nanosecond timestamps,
+	// which are converted back for the comparison.
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						switch values := rec.Column(0).(type) {
+						case *array.Date32:
+							for _, v := range values.Date32Values() {
+								checkInt32(b, row, int32(v))
+								row++
+							}
+						case *array.Time32:
+							for _, v := range values.Time32Values() {
+								checkInt32(b, row, int32(v))
+								row++
+							}
+						case *array.Time64:
+							for _, v := range values.Time64Values() {
+								checkInt64(b, row, int64(v))
+								row++
+							}
+						case *array.Timestamp:
+							for _, v := range values.TimestampValues() {
+								checkInt96(b, row, goparquet.TimeToInt96(time.Unix(0, int64(v)).UTC()))
+								row++
+							}
+						default:
+							b.Fatalf("unexpected value array %T", values)
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					switch values := chunk.(type) {
+					case *array.Date32:
+						for _, v := range values.Date32Values() {
+							checkInt32(b, row, int32(v))
+							row++
+						}
+					case *array.Time32:
+						for _, v := range values.Time32Values() {
+							checkInt32(b, row, int32(v))
+							row++
+						}
+					case *array.Time64:
+						for _, v := range values.Time64Values() {
+							checkInt64(b, row, int64(v))
+							row++
+						}
+					case *array.Timestamp:
+						for _, v := range values.TimestampValues() {
+							checkInt96(b, row, goparquet.TimeToInt96(time.Unix(0, int64(v)).UTC()))
+							row++
+						}
+					default:
+						b.Fatalf("unexpected value array %T", chunk)
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myDateTimeRecord struct {
+	physical parquet.Type
+	Int32    int32
+	Int64    int64
+	Int96    [12]byte
+}
+
+func (r *myDateTimeRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	field := obj.GetField("value")
+
+	var err error
+	switch r.physical {
+	case parquet.Type_INT32:
+		r.Int32, err = field.Int32()
+	case parquet.Type_INT64:
+		r.Int64, err = field.Int64()
+	default:
+		r.Int96, err = field.Int96()
+	}
+	return err
+}
@@ -0,0 +1,674 @@
+package benchmark_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	"github.com/segmentio/parquet-go/deprecated"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type dateTimeKind int
+
+const (
+	kindDate dateTimeKind = iota
+	kindTime
+	kindInt96
+)
+
+// dateTimeType describes a DATE column, a TIME column of a particular unit
+// or a legacy INT96 timestamp column.
+type dateTimeType struct {
+	name string
+	kind dateTimeKind
+	unit timestampUnit
+}
+
+var dateTimeTypes = []dateTimeType{
+	{name: "date", kind: kindDate},
+	{name: "time_millis", kind: kindTime, unit: timestampUnits[0]},
+	{name: "time_micros", kind: kindTime, unit: timestampUnits[1]},
+	{name: "time_nanos", kind: kindTime, unit: timestampUnits[2]},
+	{name: "int96", kind: kindInt96},
+}
+
+// physical returns the physical type of the column. TIME(MILLIS) is the
+// only TIME unit that is stored as INT32.
+func (d dateTimeType) physical() parquet.Type {
+	switch {
+	case d.kind == kindDate:
+		return parquet.Type_INT32
+	case d.kind == kindInt96:
+		return parquet.Type_INT96
+	case d.unit.duration == time.Millisecond:
+		return parquet.Type_INT32
+	default:
+		return parquet.Type_INT64
+	}
+}
+
+func (d dateTimeType) schemaDefinition() string {
+	column := "required int96 value;"
+	switch d.kind {
+	case kindDate:
+		column = "required int32 value (DATE);"
+	case kindTime:
+		physical := "int64"
+		if d.physical() == parquet.Type_INT32 {
+			physical = "int32"
+		}
+		column = fmt.Sprintf("required %s value (TIME(%s, true));", physical, d.unit.fraugster)
+	}
+	return fmt.Sprintf(`message test {
+	%s
+}`, column)
+}
+
+func (d dateTimeType) xitongsysSchema() string {
+	column := "type=INT96"
+	switch d.kind {
+	case kindDate:
+		column = "type=INT32, convertedtype=DATE, logicaltype=DATE"
+	case kindTime:
+		column = fmt.Sprintf("type=%s, logicaltype=TIME, logicaltype.isadjustedtoutc=true, logicaltype.unit=%s", d.physical(), d.unit.fraugster)
+		if d.unit.duration != time.Nanosecond {
+			column += ", convertedtype=TIME_" + d.unit.fraugster
+		}
+	}
+	return fmt.Sprintf(`{
+	"Tag": "name=test, repetitiontype=REQUIRED",
+	"Fields": [
+		{"Tag": "name=value, inname=Value, %s, repetitiontype=REQUIRED"}
+	]
+}`, column)
+}
+
+func (d dateTimeType) arrowSchema() (*schema.GroupNode, error) {
+	var node schema.Node
+	switch d.kind {
+	case kindDate:
+		node = schema.MustPrimitive(schema.NewPrimitiveNodeLogical("value", parquet3.Repetitions.Required, schema.DateLogicalType{}, parquet3.Types.Int32, 0, 0))
+	case kindTime:
+		physical := parquet3.Types.Int64
+		if d.physical() == parquet.Type_INT32 {
+			physical = parquet3.Types.Int32
+		}
+		node = schema.MustPrimitive(schema.NewPrimitiveNodeLogical("value", parquet3.Repetitions.Required, schema.NewTimeLogicalType(true, d.unit.arrow), physical, 0, 0))
+	default:
+		node = schema.MustPrimitive(schema.NewPrimitiveNode("value", parquet3.Repetitions.Required, parquet3.Types.Int96, 0, 0))
+	}
+	return schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{node}, 0)
+}
+
+// pqarrowType returns the arrow type that pqarrow maps to the column.
+// Timestamps are only written as INT96 when pqarrow is told to.
+func (d dateTimeType) pqarrowType() arrow.DataType {
+	switch {
+	case d.kind == kindDate:
+		return arrow.FixedWidthTypes.Date32
+	case d.kind == kindInt96:
+		return arrow.FixedWidthTypes.Timestamp_ns
+	case d.unit.duration == time.Millisecond:
+		return arrow.FixedWidthTypes.Time32ms
+	case d.unit.duration == time.Microsecond:
+		return arrow.FixedWidthTypes.Time64us
+	default:
+		return arrow.FixedWidthTypes.Time64ns
+	}
+}
+
+func (d dateTimeType) segmentioSchema() *parquet4.Schema {
+	node := parquet4.Leaf(parquet4.Int96Type)
+	switch d.kind {
+	case kindDate:
+		node = parquet4.Date()
+	case kindTime:
+		node = parquet4.Time(d.unit.segmentio)
+	}
+	return parquet4.NewSchema("test", parquet4.Group{
+		"value": node,
+	})
+}
+
+// annotated reports whether a column written by one of the libraries has
+// the physical type and logical or converted type of d.
+func (d dateTimeType) annotated(elem *parquet.SchemaElement) bool {
+	if elem.GetType() != d.physical() {
+		return false
+	}
+
+	lt := elem.GetLogicalType()
+	converted := parquet.ConvertedType(-1)
+	if elem.IsSetConvertedType() {
+		converted = elem.GetConvertedType()
+	}
+
+	switch d.kind {
+	case kindDate:
+		return (lt != nil && lt.IsSetDATE()) || converted == parquet.ConvertedType_DATE
+	case kindTime:
+		switch d.unit.duration {
+		case time.Millisecond:
+			return (lt != nil && lt.IsSetTIME() && lt.TIME.Unit.IsSetMILLIS()) || converted == parquet.ConvertedType_TIME_MILLIS
+		case time.Microsecond:
+			return (lt != nil && lt.IsSetTIME() && lt.TIME.Unit.IsSetMICROS()) || converted == parquet.ConvertedType_TIME_MICROS
+		default:
+			return lt != nil && lt.IsSetTIME() && lt.TIME.Unit.IsSetNANOS()
+		}
+	default:
+		return true
+	}
+}
+
+// generate returns n values that can be represented exactly in the column:
+// dates at midnight UTC, times of day truncated to the TIME unit and
+// nanosecond precision event times for INT96.
+func (d dateTimeType) generate(n int) []time.Time {
+	switch d.kind {
+	case kindDate:
+		data := make([]time.Time, n)
+		start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := range data {
+			data[i] = start.AddDate(0, 0, rand.Intn(30*365))
+		}
+		return data
+	case kindTime:
+		data := make([]time.Time, n)
+		day := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+		for i := range data {
+			data[i] = day.Add(time.Duration(rand.Int63n(int64(24 * time.Hour))).Truncate(d.unit.duration))
+		}
+		return data
+	default:
+		return generateEventTimes(n)
+	}
+}
+
+// raw returns t in the representation that parquet-go uses for the
+// column's physical type: an int32, an int64 or a [12]byte.
+func (d dateTimeType) raw(t time.Time) interface{} {
+	switch d.kind {
+	case kindDate:
+		return int32(t.Unix() / (24 * 60 * 60))
+	case kindTime:
+		return d.rawTime(nanosOfDay(t))
+	default:
+		return goparquet.TimeToInt96(t)
+	}
+}
+
+func (d dateTimeType) rawTime(nanos int64) interface{} {
+	v := nanos / int64(d.unit.duration)
+	if d.physical() == parquet.Type_INT32 {
+		return int32(v)
+	}
+	return v
+}
+
+func nanosOfDay(t time.Time) int64 {
+	return int64(t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())))
+}
+
+func segmentioInt96(v [12]byte) deprecated.Int96 {
+	return deprecated.Int96{
+		binary.LittleEndian.Uint32(v[0:4]),
+		binary.LittleEndian.Uint32(v[4:8]),
+		binary.LittleEndian.Uint32(v[8:12]),
+	}
+}
+
+func int96Bytes(v deprecated.Int96) (buf [12]byte) {
+	binary.LittleEndian.PutUint32(buf[0:4], v[0])
+	binary.LittleEndian.PutUint32(buf[4:8], v[1])
+	binary.LittleEndian.PutUint32(buf[8:12], v[2])
+	return buf
+}
+
+// verifyDateTimeFile reads back a file and checks that the column's type
+// and all values survived the round trip unchanged.
+func verifyDateTimeFile(b *testing.B, filename string, d dateTimeType, expected []interface{}) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	if elem := r.GetSchemaDefinition().SubSchema("value").SchemaElement(); !d.annotated(elem) {
+		b.Fatalf("%s: column is %s (logical type %v, converted type %v), expected %s", filename, elem.GetType(), elem.GetLogicalType(), elem.ConvertedType, d.name)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(expected) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(expected))
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+
+		if row["value"] != expected[i] {
+			b.Fatalf("%s: row %d is %v, expected %v", filename, i, row["value"], expected[i])
+		}
+	}
+}
+
+func BenchmarkDateTimeWriting(b *testing.B) {
+	numRecords := 1000000
+
+	for _, dt := range dateTimeTypes {
+		dt := dt
+
+		b.Run(dt.name, func(b *testing.B) {
+			prefix := "datetimewr_" + dt.name + "_"
+
+			times := dt.generate(numRecords)
+
+			data := make([]interface{}, numRecords)
+			for i, t := range times {
+				data[i] = dt.raw(t)
+			}
+
+			benchmarkDateTimeWriting(b, times, data, dt, prefix)
+		})
+	}
+}
+
+func benchmarkDateTimeWriting(b *testing.B, times []time.Time, data []interface{}, dt dateTimeType, prefix string) {
+	// The values are unpacked into slices of the column's physical type,
+	// and into the values that xitongsys and segmentio take, so that the
+	// writers don't time unboxing them.
+	var (
+		int32s        []int32
+		int64s        []int64
+		int96s        []parquet3.Int96
+		int96Strings  []string
+		segmentioVals = make([]parquet4.Value, len(data))
+	)
+	switch dt.physical() {
+	case parquet.Type_INT32:
+		int32s = make([]int32, len(data))
+		for i, v := range data {
+			int32s[i] = v.(int32)
+			segmentioVals[i] = parquet4.ValueOf(int32s[i]).Level(0, 0, 0)
+		}
+	case parquet.Type_INT64:
+		int64s = make([]int64, len(data))
+		for i, v := range data {
+			int64s[i] = v.(int64)
+			segmentioVals[i] = parquet4.ValueOf(int64s[i]).Level(0, 0, 0)
+		}
+	default:
+		int96s = make([]parquet3.Int96, len(data))
+		int96Strings = make([]string, len(data))
+		for i, v := range data {
+			int96s[i] = v.([12]byte)
+			int96Strings[i] = string(int96s[i][:])
+			segmentioVals[i] = parquet4.ValueOf(segmentioInt96(int96s[i])).Level(0, 0, 0)
+		}
+	}
+
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(dt.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Value time.Time `parquet:"value"`
+				}
+
+				type timeRecord struct {
+					Value floor.Time `parquet:"value"`
+				}
+
+				for _, t := range times {
+					if dt.kind == kindTime {
+						err = fw.Write(timeRecord{Value: floor.TimeFromNanoseconds(nanosOfDay(t))})
+					} else {
+						err = fw.Write(record{Value: t})
+					}
+					if err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDateTimeFile(b, parquetFilename, dt, data)
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(dt.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, v := range data {
+					if err = fw.Write(dateTimeRecord{v: v}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDateTimeFile(b, parquetFilename, dt, data)
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(dt.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, v := range data {
+					stu := map[string]interface{}{
+						"value": v,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDateTimeFile(b, parquetFilename, dt, data)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type int32Record struct {
+					Value int32
+				}
+
+				type int64Record struct {
+					Value int64
+				}
+
+				type int96Record struct {
+					Value string
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, dt.xitongsysSchema(), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, v := range int32s {
+					if err = pw.Write(int32Record{Value: v}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				for _, v := range int64s {
+					if err = pw.Write(int64Record{Value: v}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				for _, v := range int96Strings {
+					if err = pw.Write(int96Record{Value: v}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+
+		b.StopTimer()
+		verifyDateTimeFile(b, filename, dt, data)
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := dt.arrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						switch valueCol := col.(type) {
+						case *file.Int32ColumnChunkWriter:
+							err = writeBatches(len(int32s), batchSize, func(start, end int) error {
+								_, err := valueCol.WriteBatch(int32s[start:end], nil, nil)
+								return err
+							})
+						case *file.Int64ColumnChunkWriter:
+							err = writeBatches(len(int64s), batchSize, func(start, end int) error {
+								_, err := valueCol.WriteBatch(int64s[start:end], nil, nil)
+								return err
+							})
+						case *file.Int96ColumnChunkWriter:
+							err = writeBatches(len(int96s), batchSize, func(start, end int) error {
+								_, err := valueCol.WriteBatch(int96s[start:end], nil, nil)
+								return err
+							})
+						default:
+							b.Fatalf("unexpected value column %T", col)
+						}
+						if err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						col.Close()
+
+						defer rg.Close()
+					}()
+				}
+
+				b.StopTimer()
+				verifyDateTimeFile(b, filename, dt, data)
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "value", Type: dt.pqarrowType()}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				switch valueBld := bld.Field(0).(type) {
+				case *array.Date32Builder:
+					valueBld.Reserve(len(times))
+					for _, t := range times {
+						valueBld.UnsafeAppend(arrow.Date32(t.Unix() / (24 * 60 * 60)))
+					}
+				case *array.Time32Builder:
+					valueBld.Reserve(len(times))
+					for _, t := range times {
+						valueBld.UnsafeAppend(arrow.Time32(nanosOfDay(t) / int64(dt.unit.duration)))
+					}
+				case *array.Time64Builder:
+					valueBld.Reserve(len(times))
+					for _, t := range times {
+						valueBld.UnsafeAppend(arrow.Time64(nanosOfDay(t) / int64(dt.unit.duration)))
+					}
+				case *array.TimestampBuilder:
+					valueBld.Reserve(len(times))
+					for _, t := range times {
+						valueBld.UnsafeAppend(arrow.Timestamp(t.UnixNano()))
+					}
+				default:
+					b.Fatalf("unexpected value builder %T", valueBld)
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec, pqarrow.WithDeprecatedInt96Timestamps(dt.kind == kindInt96)); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDateTimeFile(b, filename, dt, data)
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, dt.segmentioSchema(), parquet4.Compression(&snappy.Codec{}))
+
+				row := make(parquet4.Row, 1)
+
+				for _, v := range segmentioVals {
+					row[0] = v
+					if err := wr.WriteRow(row); err != nil {
+						b.Fatalf("WriteRow failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDateTimeFile(b, parquetFilename, dt, data)
+	})
+}
+
+type dateTimeRecord struct {
+	v interface{}
+}
+
+func (r dateTimeRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	switch v := r.v.(type) {
+	case int32:
+		obj.AddField("value").SetInt32(v)
+	case int64:
+		obj.AddField("value").SetInt64(v)
+	case [12]byte:
+		obj.AddField("value").SetInt96(v)
+	default:
+		return fmt.Errorf("unexpected value of type %T", r.v)
+	}
+	return nil
+}
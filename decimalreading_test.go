@@ -0,0 +1,459 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/decimal128"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkDecimalReading(b *testing.B) {
+	numRecords := 1000000
+
+	for _, dec := range decimalTypes {
+		dec := dec
+
+		b.Run(dec.name, func(b *testing.B) {
+			data := generateDecimals(numRecords, dec)
+			b.ResetTimer()
+
+			benchmarkDecimalReading(b, data, dec, "decimal_"+dec.name+"_")
+		})
+	}
+}
+
+// benchmarkDecimalReading compares every decoded value against the
+// original data, so the reading benchmarks double as a check that each
+// library reads the decimals exactly.
+func benchmarkDecimalReading(b *testing.B, data decimalColumn, dec decimalType, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(dec.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for i := 0; i < data.len(); i++ {
+		if err = fw.Write(newDecimalRecord(dec, data, i)); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	checkInt := func(b *testing.B, row int, v int64) {
+		if row >= data.len() {
+			b.Fatalf("read more than the %d rows that were written", data.len())
+		}
+		if v != data.ints[row] {
+			b.Fatalf("row %d is %d, expected %d", row, v, data.ints[row])
+		}
+	}
+
+	// checkFixed compares FIXED_LEN_BYTE_ARRAY values byte for byte.
+	checkFixed := func(b *testing.B, row int, v []byte) {
+		if row >= data.len() {
+			b.Fatalf("read more than the %d rows that were written", data.len())
+		}
+		if !bytes.Equal(v, data.fixed[row]) {
+			b.Fatalf("row %d is %x (%s), expected %x (%s)", row, v, fixedToDecimal(v), data.fixed[row], data.values[row])
+		}
+	}
+
+	// checkFixedString is checkFixed for libraries that return the bytes as
+	// a string.
+	checkFixedString := func(b *testing.B, row int, v string) {
+		if row >= data.len() {
+			b.Fatalf("read more than the %d rows that were written", data.len())
+		}
+		if v != string(data.fixed[row]) {
+			b.Fatalf("row %d is %x (%s), expected %x (%s)", row, v, fixedToDecimal([]byte(v)), data.fixed[row], data.values[row])
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for row := 0; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					switch v := values["amount"].(type) {
+					case int32:
+						checkInt(b, row, int64(v))
+					case int64:
+						checkInt(b, row, v)
+					case []byte:
+						checkFixed(b, row, v)
+					default:
+						b.Fatalf("row %d: unexpected decimal value of type %T", row, v)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Amount int64 `parquet:"amount"`
+		}
+
+		type fixedReflectRecord struct {
+			Amount []byte `parquet:"amount"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					if dec.size > 0 {
+						var rec fixedReflectRecord
+						if err := r.Scan(&rec); err != nil {
+							b.Fatalf("Scan failed: %v", err)
+						}
+						checkFixed(b, row, rec.Amount)
+					} else {
+						var rec reflectRecord
+						if err := r.Scan(&rec); err != nil {
+							b.Fatalf("Scan failed: %v", err)
+						}
+						checkInt(b, row, rec.Amount)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					rec := myDecimalRecord{physical: dec.physical}
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					if dec.size > 0 {
+						checkFixed(b, row, rec.Fixed)
+					} else {
+						checkInt(b, row, rec.Amount)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type int32Record struct {
+			Amount int32
+		}
+
+		type int64Record struct {
+			Amount int64
+		}
+
+		type fixedRecord struct {
+			Amount string
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, dec.xitongsysSchema(), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+
+					switch dec.physical {
+					case parquet.Type_INT32:
+						rec := make([]int32Record, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								checkInt(b, row, int64(r.Amount))
+								row++
+							}
+						}
+					case parquet.Type_INT64:
+						rec := make([]int64Record, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								checkInt(b, row, r.Amount)
+								row++
+							}
+						}
+					default:
+						rec := make([]fixedRecord, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								checkFixedString(b, row, r.Amount)
+								row++
+							}
+						}
+					}
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				for n := 0; ; n++ {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					switch row[0].Kind() {
+					case parquet4.Int32:
+						checkInt(b, n, int64(row[0].Int32()))
+					case parquet4.Int64:
+						checkInt(b, n, row[0].Int64())
+					default:
+						checkFixed(b, n, row[0].ByteArray())
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				int32Values := make([]int32, batchSize)
+				int64Values := make([]int64, batchSize)
+				fixedValues := make([]parquet3.FixedLenByteArray, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						row := 0
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							switch col := r.RowGroup(rg).Column(0).(type) {
+							case *file.Int32ColumnChunkReader:
+								for col.HasNext() {
+									_, n, err := col.ReadBatch(int64(len(int32Values)), int32Values, nil, nil)
+									if err != nil {
+										b.Fatalf("ReadBatch failed: %v", err)
+									}
+									for _, v := range int32Values[:n] {
+										checkInt(b, row, int64(v))
+										row++
+									}
+								}
+							case *file.Int64ColumnChunkReader:
+								for col.HasNext() {
+									_, n, err := col.ReadBatch(int64(len(int64Values)), int64Values, nil, nil)
+									if err != nil {
+										b.Fatalf("ReadBatch failed: %v", err)
+									}
+									for _, v := range int64Values[:n] {
+										checkInt(b, row, v)
+										row++
+									}
+								}
+							case *file.FixedLenByteArrayColumnChunkReader:
+								for col.HasNext() {
+									_, n, err := col.ReadBatch(int64(len(fixedValues)), fixedValues, nil, nil)
+									if err != nil {
+										b.Fatalf("ReadBatch failed: %v", err)
+									}
+									for _, v := range fixedValues[:n] {
+										checkFixed(b, row, v)
+										row++
+									}
+								}
+							default:
+								b.Fatalf("unexpected amount column %T", col)
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	// pqarrow reads all decimals as 128 bit values, whichever physical type
+	// they are stored as.
+	want := make([]decimal128.Num, data.len())
+	for i, v := range data.values {
+		want[i] = decimal128.FromBigInt(v)
+	}
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						values := rec.Column(0).(*array.Decimal128)
+						for j := 0; j < values.Len(); j++ {
+							if v := values.Value(j); v != want[row] {
+								b.Fatalf("row %d is %s, expected %s", row, v.BigInt(), data.values[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != data.len() {
+						b.Fatalf("read %d rows, expected %d", row, data.len())
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					values := chunk.(*array.Decimal128)
+					for j := 0; j < values.Len(); j++ {
+						if v := values.Value(j); v != want[row] {
+							b.Fatalf("row %d is %s, expected %s", row, v.BigInt(), data.values[row])
+						}
+						row++
+					}
+				}
+				if row != data.len() {
+					b.Fatalf("read %d rows, expected %d", row, data.len())
+				}
+			}()
+		}
+	})
+}
+
+type myDecimalRecord struct {
+	physical parquet.Type
+	Amount   int64
+	Fixed    []byte
+}
+
+func (r *myDecimalRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	field := obj.GetField("amount")
+
+	switch r.physical {
+	case parquet.Type_INT32:
+		i32, err := field.Int32()
+		if err != nil {
+			return err
+		}
+		r.Amount = int64(i32)
+	case parquet.Type_INT64:
+		i64, err := field.Int64()
+		if err != nil {
+			return err
+		}
+		r.Amount = i64
+	default:
+		buf, err := field.ByteArray()
+		if err != nil {
+			return err
+		}
+		r.Fixed = buf
+	}
+	return nil
+}
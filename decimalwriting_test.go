@@ -0,0 +1,601 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/decimal128"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// decimalType describes a DECIMAL column with a particular precision and
+// scale, stored as INT32, INT64 or as a FIXED_LEN_BYTE_ARRAY of size bytes.
+type decimalType struct {
+	name      string
+	physical  parquet.Type
+	precision int
+	scale     int
+	size      int
+}
+
+var decimalTypes = []decimalType{
+	{name: "int32_9_2", physical: parquet.Type_INT32, precision: 9, scale: 2},
+	{name: "int64_18_4", physical: parquet.Type_INT64, precision: 18, scale: 4},
+	{name: "fixed_28_6", physical: parquet.Type_FIXED_LEN_BYTE_ARRAY, precision: 28, scale: 6, size: 12},
+	{name: "fixed_38_10", physical: parquet.Type_FIXED_LEN_BYTE_ARRAY, precision: 38, scale: 10, size: 16},
+}
+
+func (d decimalType) schemaDefinition() string {
+	physical := "int32"
+	switch d.physical {
+	case parquet.Type_INT64:
+		physical = "int64"
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		physical = fmt.Sprintf("fixed_len_byte_array(%d)", d.size)
+	}
+	return fmt.Sprintf(`message test {
+	required %s amount (DECIMAL(%d, %d));
+}`, physical, d.precision, d.scale)
+}
+
+func (d decimalType) xitongsysSchema() string {
+	physical := "type=INT32"
+	switch d.physical {
+	case parquet.Type_INT64:
+		physical = "type=INT64"
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		physical = fmt.Sprintf("type=FIXED_LEN_BYTE_ARRAY, length=%d", d.size)
+	}
+	return fmt.Sprintf(`{
+	"Tag": "name=test, repetitiontype=REQUIRED",
+	"Fields": [
+		{"Tag": "name=amount, inname=Amount, %s, convertedtype=DECIMAL, precision=%d, scale=%d, logicaltype=DECIMAL, logicaltype.precision=%d, logicaltype.scale=%d, repetitiontype=REQUIRED"}
+	]
+}`, physical, d.precision, d.scale, d.precision, d.scale)
+}
+
+func (d decimalType) arrowSchema() (*schema.GroupNode, error) {
+	physical := parquet3.Types.Int32
+	switch d.physical {
+	case parquet.Type_INT64:
+		physical = parquet3.Types.Int64
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		physical = parquet3.Types.FixedLenByteArray
+	}
+	return schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("amount", parquet3.Repetitions.Required, schema.NewDecimalLogicalType(int32(d.precision), int32(d.scale)), physical, d.size, 0)),
+	}, 0)
+}
+
+func (d decimalType) segmentioSchema() *parquet4.Schema {
+	physical := parquet4.Int32Type
+	switch d.physical {
+	case parquet.Type_INT64:
+		physical = parquet4.Int64Type
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		physical = parquet4.FixedLenByteArrayType(d.size)
+	}
+	return parquet4.NewSchema("test", parquet4.Group{
+		"amount": parquet4.Decimal(d.scale, d.precision, physical),
+	})
+}
+
+// value returns the unscaled value of row i in the Go type that parquet-go
+// expects for the column's physical type.
+func (d decimalType) value(data decimalColumn, i int) interface{} {
+	switch d.physical {
+	case parquet.Type_INT64:
+		return data.ints[i]
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		return data.fixed[i]
+	default:
+		return int32(data.ints[i])
+	}
+}
+
+func (d decimalType) segmentioValue(data decimalColumn, i int) parquet4.Value {
+	switch d.physical {
+	case parquet.Type_INT64:
+		return parquet4.ValueOf(data.ints[i])
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		return parquet4.FixedLenByteArray.Value(data.fixed[i])
+	default:
+		return parquet4.ValueOf(int32(data.ints[i]))
+	}
+}
+
+// decimalColumn holds unscaled decimal values, and the same values in the
+// representation of the column's physical type: ints for INT32 and INT64,
+// fixed for FIXED_LEN_BYTE_ARRAY.
+type decimalColumn struct {
+	values []*big.Int
+	ints   []int64
+	fixed  [][]byte
+}
+
+func (c decimalColumn) len() int {
+	return len(c.values)
+}
+
+// generateDecimals returns n unscaled decimal values that use the full
+// precision of the column.
+func generateDecimals(n int, d decimalType) decimalColumn {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.precision)), nil)
+	rnd := rand.New(rand.NewSource(rand.Int63()))
+
+	data := decimalColumn{values: make([]*big.Int, n)}
+	if d.size > 0 {
+		data.fixed = make([][]byte, n)
+	} else {
+		data.ints = make([]int64, n)
+	}
+
+	for i := range data.values {
+		v := new(big.Int).Rand(rnd, max)
+		if rnd.Intn(2) == 0 {
+			v.Neg(v)
+		}
+		data.values[i] = v
+		if d.size > 0 {
+			data.fixed[i] = decimalToFixed(v, d.size)
+		} else {
+			data.ints[i] = v.Int64()
+		}
+	}
+	return data
+}
+
+// decimalToFixed encodes an unscaled decimal value as a big-endian two's
+// complement number of size bytes.
+func decimalToFixed(v *big.Int, size int) []byte {
+	buf := make([]byte, size)
+	if v.Sign() < 0 {
+		v = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), uint(8*size)), v)
+	}
+	return v.FillBytes(buf)
+}
+
+// fixedToDecimal is the inverse of decimalToFixed.
+func fixedToDecimal(buf []byte) *big.Int {
+	v := new(big.Int).SetBytes(buf)
+	if len(buf) > 0 && buf[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(buf))))
+	}
+	return v
+}
+
+// verifyDecimalFile reads back a file and checks that the DECIMAL annotation
+// survived the round trip, and that every value is unchanged: INT32 and
+// INT64 values must be equal, FIXED_LEN_BYTE_ARRAY values byte for byte.
+func verifyDecimalFile(b *testing.B, filename string, d decimalType, data decimalColumn) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	elem := r.GetSchemaDefinition().SubSchema("amount").SchemaElement()
+	precision, scale := elem.GetPrecision(), elem.GetScale()
+	if lt := elem.GetLogicalType(); lt != nil && lt.IsSetDECIMAL() {
+		precision, scale = lt.DECIMAL.Precision, lt.DECIMAL.Scale
+	}
+	if elem.GetType() != d.physical || int(precision) != d.precision || int(scale) != d.scale {
+		b.Fatalf("%s: column is %s DECIMAL(%d, %d), expected %s DECIMAL(%d, %d)", filename, elem.GetType(), precision, scale, d.physical, d.precision, d.scale)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != data.len() {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, data.len())
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+		if i >= data.len() {
+			b.Fatalf("%s: read more than the %d rows that were written", filename, data.len())
+		}
+
+		switch v := row["amount"].(type) {
+		case int32:
+			if int64(v) != data.values[i].Int64() {
+				b.Fatalf("%s: row %d is %d, expected %s", filename, i, v, data.values[i])
+			}
+		case int64:
+			if v != data.values[i].Int64() {
+				b.Fatalf("%s: row %d is %d, expected %s", filename, i, v, data.values[i])
+			}
+		case []byte:
+			if want := decimalToFixed(data.values[i], d.size); !bytes.Equal(v, want) {
+				b.Fatalf("%s: row %d is %x (%s), expected %x (%s)", filename, i, v, fixedToDecimal(v), want, data.values[i])
+			}
+		default:
+			b.Fatalf("%s: row %d: unexpected decimal value of type %T", filename, i, v)
+		}
+	}
+}
+
+func BenchmarkDecimalWriting(b *testing.B) {
+	numRecords := 1000000
+
+	for _, dec := range decimalTypes {
+		dec := dec
+
+		b.Run(dec.name, func(b *testing.B) {
+			prefix := "decimalwr_" + dec.name + "_"
+
+			data := generateDecimals(numRecords, dec)
+
+			benchmarkDecimalWriting(b, data, dec, prefix)
+		})
+	}
+}
+
+func benchmarkDecimalWriting(b *testing.B, data decimalColumn, dec decimalType, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(dec.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Amount int64 `parquet:"amount"`
+				}
+
+				type fixedRecord struct {
+					Amount []byte `parquet:"amount"`
+				}
+
+				for i := 0; i < data.len(); i++ {
+					if dec.size > 0 {
+						err = fw.Write(fixedRecord{Amount: data.fixed[i]})
+					} else {
+						err = fw.Write(record{Amount: data.ints[i]})
+					}
+					if err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDecimalFile(b, parquetFilename, dec, data)
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(dec.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for i := 0; i < data.len(); i++ {
+					if err = fw.Write(newDecimalRecord(dec, data, i)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDecimalFile(b, parquetFilename, dec, data)
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(dec.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for i := 0; i < data.len(); i++ {
+					stu := map[string]interface{}{
+						"amount": dec.value(data, i),
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDecimalFile(b, parquetFilename, dec, data)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type int32Record struct {
+					Amount int32
+				}
+
+				type int64Record struct {
+					Amount int64
+				}
+
+				type fixedRecord struct {
+					Amount string
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, dec.xitongsysSchema(), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for i := 0; i < data.len(); i++ {
+					switch dec.physical {
+					case parquet.Type_INT32:
+						err = pw.Write(int32Record{Amount: int32(data.ints[i])})
+					case parquet.Type_INT64:
+						err = pw.Write(int64Record{Amount: data.ints[i]})
+					default:
+						err = pw.Write(fixedRecord{Amount: string(data.fixed[i])})
+					}
+					if err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+
+		b.StopTimer()
+		verifyDecimalFile(b, filename, dec, data)
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		// the values are converted to the types the column writers take
+		// once, so that the batch sizes only differ in the calls.
+		int32s := make([]int32, len(data.ints))
+		for i, v := range data.ints {
+			int32s[i] = int32(v)
+		}
+		fixed := make([]parquet3.FixedLenByteArray, len(data.fixed))
+		for i, v := range data.fixed {
+			fixed[i] = v
+		}
+
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := dec.arrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						switch amountCol := col.(type) {
+						case *file.Int32ColumnChunkWriter:
+							err = writeBatches(len(int32s), batchSize, func(start, end int) error {
+								_, err := amountCol.WriteBatch(int32s[start:end], nil, nil)
+								return err
+							})
+						case *file.Int64ColumnChunkWriter:
+							err = writeBatches(len(data.ints), batchSize, func(start, end int) error {
+								_, err := amountCol.WriteBatch(data.ints[start:end], nil, nil)
+								return err
+							})
+						case *file.FixedLenByteArrayColumnChunkWriter:
+							err = writeBatches(len(fixed), batchSize, func(start, end int) error {
+								_, err := amountCol.WriteBatch(fixed[start:end], nil, nil)
+								return err
+							})
+						default:
+							b.Fatalf("unexpected amount column %T", col)
+						}
+						if err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						col.Close()
+
+						defer rg.Close()
+					}()
+				}
+
+				b.StopTimer()
+				verifyDecimalFile(b, filename, dec, data)
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "amount", Type: &arrow.Decimal128Type{Precision: int32(dec.precision), Scale: int32(dec.scale)}}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				amountBld := bld.Field(0).(*array.Decimal128Builder)
+				amountBld.Reserve(data.len())
+				for _, v := range data.values {
+					amountBld.UnsafeAppend(decimal128.FromBigInt(v))
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+
+		// pqarrow stores all decimals as FIXED_LEN_BYTE_ARRAY of the
+		// smallest size that fits the precision.
+		stored := dec
+		stored.physical = parquet.Type_FIXED_LEN_BYTE_ARRAY
+		stored.size = int(pqarrow.DecimalSize(int32(dec.precision)))
+		verifyDecimalFile(b, filename, stored, data)
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, dec.segmentioSchema(), parquet4.Compression(&snappy.Codec{}))
+
+				row := make(parquet4.Row, 1)
+
+				for i := 0; i < data.len(); i++ {
+					row[0] = dec.segmentioValue(data, i).Level(0, 0, 0)
+					if err := wr.WriteRow(row); err != nil {
+						b.Fatalf("WriteRow failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDecimalFile(b, parquetFilename, dec, data)
+	})
+}
+
+type decimalRecord struct {
+	physical parquet.Type
+	v        int64
+	fixed    []byte
+}
+
+func newDecimalRecord(dec decimalType, data decimalColumn, i int) decimalRecord {
+	if dec.size > 0 {
+		return decimalRecord{physical: dec.physical, fixed: data.fixed[i]}
+	}
+	return decimalRecord{physical: dec.physical, v: data.ints[i]}
+}
+
+func (r decimalRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	switch r.physical {
+	case parquet.Type_INT32:
+		obj.AddField("amount").SetInt32(int32(r.v))
+	case parquet.Type_INT64:
+		obj.AddField("amount").SetInt64(r.v)
+	default:
+		obj.AddField("amount").SetByteArray(r.fixed)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+)
+
+func BenchmarkDeepNestedReading(b *testing.B) {
+	data := generateDeepDocuments(50000)
+	expected := shredDeepDocuments(data)
+
+	schemaDef, err := parquetschema.ParseSchemaDefinition(deepSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := "deeprd_testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, doc := range data {
+		r := deepRecord(doc)
+		if err = fw.Write(&r); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	// readColumns reads the leaf columns with their levels and compares
+	// them to the shredded documents. The pqarrow benchmarks are skipped
+	// if the documents or batches hit arrow v8's struct bitmap overrun.
+	readColumns := func(b *testing.B, read deepColumnReader) {
+		cols, err := read(parquetFilename)
+		if errors.Is(err, errPqarrowStructBitmap) {
+			b.Skip(err)
+		}
+		if err != nil {
+			b.Fatalf("Reading columns failed: %v", err)
+		}
+		if err := cols.compare(expected); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+
+	// parquet-go writes these documents correctly, but its reader loses
+	// the elements that follow a null or empty element in a nested list,
+	// which also shifts all subsequent rows.
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		b.Skip("parquet-go's reader loses list elements that follow a null or empty nested element")
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		b.Skip("floor's reflection unmarshaller fails on null list elements and empty lists")
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		b.Skip("parquet-go's reader loses list elements that follow a null or empty nested element")
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		b.Skip("xitongsys can't find the columns below a list of structs")
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			readColumns(b, readSegmentioDeepColumns)
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				read := arrowDeepColumnReader(batchSize)
+				for i := 0; i < b.N; i++ {
+					readColumns(b, read)
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				read := pqarrowRecordDeepColumnReader(batchSize)
+				for i := 0; i < b.N; i++ {
+					readColumns(b, read)
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			readColumns(b, readPqarrowDeepColumns)
+		}
+	})
+}
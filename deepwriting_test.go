@@ -0,0 +1,1219 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+)
+
+// deepSchema nests repeated and optional groups up to five levels deep:
+// matrix is a list of lists, sections is a list of structs that contain
+// a list of structs that contain a list. Every group is optional, so
+// null and empty lists as well as null elements all have their own
+// definition level.
+const deepSchema = `message document {
+	required int64 id;
+	optional group matrix (LIST) {
+		repeated group list {
+			optional group element (LIST) {
+				repeated group list {
+					optional int64 element;
+				}
+			}
+		}
+	}
+	optional group sections (LIST) {
+		repeated group list {
+			optional group element {
+				optional binary heading (STRING);
+				optional group paragraphs (LIST) {
+					repeated group list {
+						optional group element {
+							optional group tags (LIST) {
+								repeated group list {
+									optional binary element (STRING);
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+// deepDocument is the Go representation of deepSchema. A nil slice is a
+// null list, a non-nil empty slice an empty list, and nil pointers are
+// null elements.
+type deepDocument struct {
+	ID       int64
+	Matrix   [][]*int64
+	Sections []*deepSection
+}
+
+type deepSection struct {
+	Heading    *string
+	Paragraphs []*deepParagraph
+}
+
+type deepParagraph struct {
+	Tags []*string
+}
+
+// deepLeaves describes the leaf columns of deepSchema in schema order.
+var deepLeaves = [...]struct {
+	path   string
+	maxDef int16
+	int64s bool
+}{
+	{"id", 0, true},
+	{"matrix.list.element.list.element", 5, true},
+	{"sections.list.element.heading", 4, false},
+	{"sections.list.element.paragraphs.list.element.tags.list.element", 9, false},
+}
+
+// deepColumn is a leaf column of deepSchema the way it is stored in a
+// file: one repetition and definition level per value slot, and the
+// values of the slots that are defined up to the maximum definition
+// level.
+type deepColumn struct {
+	repLevels  []int16
+	defLevels  []int16
+	int64s     []int64
+	byteArrays []parquet3.ByteArray
+}
+
+type deepColumns [len(deepLeaves)]deepColumn
+
+func (c *deepColumn) level(rep, def int16) {
+	c.repLevels = append(c.repLevels, rep)
+	c.defLevels = append(c.defLevels, def)
+}
+
+func (c *deepColumns) reset() {
+	for i := range c {
+		c[i].repLevels = c[i].repLevels[:0]
+		c[i].defLevels = c[i].defLevels[:0]
+		c[i].int64s = c[i].int64s[:0]
+		c[i].byteArrays = c[i].byteArrays[:0]
+	}
+}
+
+// add shreds a document into the leaf columns.
+func (c *deepColumns) add(doc deepDocument) {
+	c[0].level(0, 0)
+	c[0].int64s = append(c[0].int64s, doc.ID)
+
+	matrix := &c[1]
+	switch {
+	case doc.Matrix == nil:
+		matrix.level(0, 0)
+	case len(doc.Matrix) == 0:
+		matrix.level(0, 1)
+	}
+	for i, row := range doc.Matrix {
+		rep := int16(1)
+		if i == 0 {
+			rep = 0
+		}
+		switch {
+		case row == nil:
+			matrix.level(rep, 2)
+		case len(row) == 0:
+			matrix.level(rep, 3)
+		}
+		for j, v := range row {
+			if j > 0 {
+				rep = 2
+			}
+			if v == nil {
+				matrix.level(rep, 4)
+				continue
+			}
+			matrix.level(rep, 5)
+			matrix.int64s = append(matrix.int64s, *v)
+		}
+	}
+
+	headings, tags := &c[2], &c[3]
+	switch {
+	case doc.Sections == nil:
+		headings.level(0, 0)
+		tags.level(0, 0)
+	case len(doc.Sections) == 0:
+		headings.level(0, 1)
+		tags.level(0, 1)
+	}
+	for i, s := range doc.Sections {
+		rep := int16(1)
+		if i == 0 {
+			rep = 0
+		}
+		if s == nil {
+			headings.level(rep, 2)
+			tags.level(rep, 2)
+			continue
+		}
+
+		if s.Heading == nil {
+			headings.level(rep, 3)
+		} else {
+			headings.level(rep, 4)
+			headings.byteArrays = append(headings.byteArrays, parquet3.ByteArray(*s.Heading))
+		}
+
+		switch {
+		case s.Paragraphs == nil:
+			tags.level(rep, 3)
+		case len(s.Paragraphs) == 0:
+			tags.level(rep, 4)
+		}
+		for j, p := range s.Paragraphs {
+			if j > 0 {
+				rep = 2
+			}
+			switch {
+			case p == nil:
+				tags.level(rep, 5)
+				continue
+			case p.Tags == nil:
+				tags.level(rep, 6)
+			case len(p.Tags) == 0:
+				tags.level(rep, 7)
+			}
+			for k, t := range p.Tags {
+				if k > 0 {
+					rep = 3
+				}
+				if t == nil {
+					tags.level(rep, 8)
+					continue
+				}
+				tags.level(rep, 9)
+				tags.byteArrays = append(tags.byteArrays, parquet3.ByteArray(*t))
+			}
+		}
+	}
+}
+
+// compare returns an error describing the first difference between the
+// columns and the expected ones.
+func (c *deepColumns) compare(expected *deepColumns) error {
+	for i := range c {
+		got, want := &c[i], &expected[i]
+		path := deepLeaves[i].path
+
+		if len(got.defLevels) != len(want.defLevels) {
+			return fmt.Errorf("%s has %d levels, expected %d", path, len(got.defLevels), len(want.defLevels))
+		}
+		for j := range want.defLevels {
+			if got.repLevels[j] != want.repLevels[j] || got.defLevels[j] != want.defLevels[j] {
+				return fmt.Errorf("%s level %d is rep=%d def=%d, expected rep=%d def=%d", path, j, got.repLevels[j], got.defLevels[j], want.repLevels[j], want.defLevels[j])
+			}
+		}
+
+		if len(got.int64s) != len(want.int64s) || len(got.byteArrays) != len(want.byteArrays) {
+			return fmt.Errorf("%s has %d values, expected %d", path, len(got.int64s)+len(got.byteArrays), len(want.int64s)+len(want.byteArrays))
+		}
+		for j := range want.int64s {
+			if got.int64s[j] != want.int64s[j] {
+				return fmt.Errorf("%s value %d is %d, expected %d", path, j, got.int64s[j], want.int64s[j])
+			}
+		}
+		for j := range want.byteArrays {
+			if !bytes.Equal(got.byteArrays[j], want.byteArrays[j]) {
+				return fmt.Errorf("%s value %d is %q, expected %q", path, j, got.byteArrays[j], want.byteArrays[j])
+			}
+		}
+	}
+	return nil
+}
+
+func shredDeepDocuments(data []deepDocument) *deepColumns {
+	cols := new(deepColumns)
+	for _, doc := range data {
+		cols.add(doc)
+	}
+	return cols
+}
+
+// deepColumnReader reads the leaf columns of a file written with
+// deepSchema.
+type deepColumnReader func(filename string) (*deepColumns, error)
+
+// arrowDeepColumnReader returns a deepColumnReader that reads the leaf
+// columns with their levels through arrow's column chunk readers, which
+// read batchSize levels at a time.
+func arrowDeepColumnReader(batchSize int) deepColumnReader {
+	return func(filename string) (*deepColumns, error) {
+		return readArrowDeepColumns(filename, batchSize)
+	}
+}
+
+// readArrowDeepColumns reads the leaf columns of filename, batchSize
+// levels at a time.
+func readArrowDeepColumns(filename string, batchSize int) (*deepColumns, error) {
+	r, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var (
+		cols            = new(deepColumns)
+		int64Values     = make([]int64, batchSize)
+		byteArrayValues = make([]parquet3.ByteArray, batchSize)
+		defLevels       = make([]int16, batchSize)
+		repLevels       = make([]int16, batchSize)
+	)
+
+	for rg := 0; rg < r.NumRowGroups(); rg++ {
+		rgr := r.RowGroup(rg)
+
+		for i := range cols {
+			c := &cols[i]
+
+			for col := rgr.Column(i); col.HasNext(); {
+				var (
+					numLevels int64
+					numValues int
+					err       error
+				)
+
+				switch col := col.(type) {
+				case *file.Int64ColumnChunkReader:
+					numLevels, numValues, err = col.ReadBatch(int64(len(int64Values)), int64Values, defLevels, repLevels)
+					c.int64s = append(c.int64s, int64Values[:numValues]...)
+				case *file.ByteArrayColumnChunkReader:
+					numLevels, numValues, err = col.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, defLevels, repLevels)
+					// the values point into the page buffers, which
+					// don't outlive the reader.
+					for _, v := range byteArrayValues[:numValues] {
+						c.byteArrays = append(c.byteArrays, append(parquet3.ByteArray(nil), v...))
+					}
+				default:
+					return nil, fmt.Errorf("unexpected %s column %T", deepLeaves[i].path, col)
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				// required columns come without levels.
+				if deepLeaves[i].maxDef == 0 {
+					for j := 0; j < numValues; j++ {
+						c.level(0, 0)
+					}
+					continue
+				}
+				c.repLevels = append(c.repLevels, repLevels[:numLevels]...)
+				c.defLevels = append(c.defLevels, defLevels[:numLevels]...)
+			}
+		}
+	}
+
+	return cols, nil
+}
+
+// readSegmentioDeepColumns reads the leaf columns row by row, using the
+// levels that segmentio attaches to every value.
+func readSegmentioDeepColumns(filename string) (*deepColumns, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cols := new(deepColumns)
+
+	r := parquet4.NewReader(f)
+	var row parquet4.Row
+	for {
+		row, err = r.ReadRow(row[:0])
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		for _, v := range row {
+			i := v.Column()
+			c := &cols[i]
+			c.level(int16(v.RepetitionLevel()), int16(v.DefinitionLevel()))
+			if int16(v.DefinitionLevel()) != deepLeaves[i].maxDef {
+				continue
+			}
+			if deepLeaves[i].int64s {
+				c.int64s = append(c.int64s, v.Int64())
+			} else {
+				c.byteArrays = append(c.byteArrays, append(parquet3.ByteArray(nil), v.ByteArray()...))
+			}
+		}
+	}
+
+	return cols, nil
+}
+
+// errPqarrowStructBitmap is returned by the pqarrow deep column readers
+// when pqarrow panics while reading the columns. arrow v8's struct reader
+// writes one byte past the validity bitmap of a struct in a list if the
+// number of structs is a multiple of 8, which crashes the reader.
+var errPqarrowStructBitmap = errors.New("arrow v8's struct reader overran the validity bitmap of a struct in a list")
+
+// readPqarrowDeepTable is readPqarrowTable, but it reads the columns in
+// the calling goroutine instead of ReadTable's workers, so that the panic
+// can be recovered instead of killing the whole benchmark process.
+func readPqarrowDeepTable(filename string) (tbl arrow.Table, err error) {
+	r, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make([]int, r.MetaData().Schema.NumColumns())
+	for i := range leaves {
+		leaves[i] = i
+	}
+	rowGroups := make([]int, r.NumRowGroups())
+	for i := range rowGroups {
+		rowGroups[i] = i
+	}
+
+	readers, sc, err := fr.GetFieldReaders(context.Background(), leaves, rowGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]arrow.Column, 0, len(readers))
+	defer func() {
+		for i := range cols {
+			cols[i].Release()
+		}
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: %v", errPqarrowStructBitmap, p)
+		}
+	}()
+
+	for i, rdr := range readers {
+		chunked, err := fr.ReadColumn(rowGroups, rdr)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, *arrow.NewColumn(sc.Field(i), chunked))
+		chunked.Release()
+	}
+
+	return array.NewTable(sc, cols, r.NumRows()), nil
+}
+
+// readPqarrowDeepColumns reads the documents into an arrow table through
+// pqarrow and shreds them into leaf columns again.
+func readPqarrowDeepColumns(filename string) (*deepColumns, error) {
+	tbl, err := readPqarrowDeepTable(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer tbl.Release()
+
+	cols := new(deepColumns)
+
+	tr := array.NewTableReader(tbl, -1)
+	defer tr.Release()
+
+	for tr.Next() {
+		if err := shredPqarrowDeepRecord(cols, tr.Record()); err != nil {
+			return nil, err
+		}
+	}
+
+	return cols, nil
+}
+
+// pqarrowRecordDeepColumnReader returns a deepColumnReader that reads the
+// documents through a pqarrow record reader, which returns records of
+// batchSize rows, and shreds them into leaf columns again. The record
+// reader runs in the calling goroutine, so its panic is recovered like in
+// readPqarrowDeepTable.
+func pqarrowRecordDeepColumnReader(batchSize int) deepColumnReader {
+	return func(filename string) (cols *deepColumns, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				cols, err = nil, fmt.Errorf("%w: %v", errPqarrowStructBitmap, p)
+			}
+		}()
+
+		cols = new(deepColumns)
+		err = readPqarrowRecords(filename, batchSize, func(rec arrow.Record) error {
+			return shredPqarrowDeepRecord(cols, rec)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return cols, nil
+	}
+}
+
+// shredPqarrowDeepRecord shreds the documents of a record read through
+// pqarrow into cols.
+func shredPqarrowDeepRecord(cols *deepColumns, rec arrow.Record) error {
+	ids, ok := rec.Column(0).(*array.Int64)
+	if !ok {
+		return fmt.Errorf("unexpected id array %T", rec.Column(0))
+	}
+	matrix, ok := rec.Column(1).(*array.List)
+	if !ok {
+		return fmt.Errorf("unexpected matrix array %T", rec.Column(1))
+	}
+	sections, ok := rec.Column(2).(*array.List)
+	if !ok {
+		return fmt.Errorf("unexpected sections array %T", rec.Column(2))
+	}
+
+	matrixRows := matrix.ListValues().(*array.List)
+	cells := matrixRows.ListValues().(*array.Int64)
+	sectionStructs := sections.ListValues().(*array.Struct)
+	headings := sectionStructs.Field(0).(*array.String)
+	paragraphs := sectionStructs.Field(1).(*array.List)
+	paragraphStructs := paragraphs.ListValues().(*array.Struct)
+	tags := paragraphStructs.Field(0).(*array.List)
+	tagValues := tags.ListValues().(*array.String)
+
+	for j := 0; j < int(rec.NumRows()); j++ {
+		doc := deepDocument{ID: ids.Value(j)}
+
+		if matrix.IsValid(j) {
+			start, end := listRange(matrix, j)
+			doc.Matrix = make([][]*int64, 0, end-start)
+			for r := start; r < end; r++ {
+				if matrixRows.IsNull(r) {
+					doc.Matrix = append(doc.Matrix, nil)
+					continue
+				}
+				start, end := listRange(matrixRows, r)
+				row := make([]*int64, 0, end-start)
+				for c := start; c < end; c++ {
+					if cells.IsNull(c) {
+						row = append(row, nil)
+						continue
+					}
+					v := cells.Value(c)
+					row = append(row, &v)
+				}
+				doc.Matrix = append(doc.Matrix, row)
+			}
+		}
+
+		if sections.IsValid(j) {
+			start, end := listRange(sections, j)
+			doc.Sections = make([]*deepSection, 0, end-start)
+			for s := start; s < end; s++ {
+				if sectionStructs.IsNull(s) {
+					doc.Sections = append(doc.Sections, nil)
+					continue
+				}
+				section := &deepSection{}
+				if headings.IsValid(s) {
+					heading := headings.Value(s)
+					section.Heading = &heading
+				}
+				if paragraphs.IsValid(s) {
+					start, end := listRange(paragraphs, s)
+					section.Paragraphs = make([]*deepParagraph, 0, end-start)
+					for p := start; p < end; p++ {
+						if paragraphStructs.IsNull(p) {
+							section.Paragraphs = append(section.Paragraphs, nil)
+							continue
+						}
+						paragraph := &deepParagraph{}
+						if tags.IsValid(p) {
+							start, end := listRange(tags, p)
+							paragraph.Tags = make([]*string, 0, end-start)
+							for t := start; t < end; t++ {
+								if tagValues.IsNull(t) {
+									paragraph.Tags = append(paragraph.Tags, nil)
+									continue
+								}
+								tag := tagValues.Value(t)
+								paragraph.Tags = append(paragraph.Tags, &tag)
+							}
+						}
+						section.Paragraphs = append(section.Paragraphs, paragraph)
+					}
+				}
+				doc.Sections = append(doc.Sections, section)
+			}
+		}
+
+		cols.add(doc)
+	}
+
+	return nil
+}
+
+// verifyDeepFile reads back the leaf columns of a file and checks that
+// every repetition level, definition level and value matches the
+// shredded documents.
+func verifyDeepFile(b *testing.B, filename string, expected *deepColumns, read deepColumnReader) {
+	cols, err := read(filename)
+	if err != nil {
+		b.Fatalf("%s: reading columns failed: %v", filename, err)
+	}
+	if err := cols.compare(expected); err != nil {
+		b.Fatalf("%s: %v", filename, err)
+	}
+}
+
+var deepTags = []string{"go", "parquet", "arrow", "benchmark", "nested", "list", "struct", "levels"}
+
+// deepSeed seeds the generated documents, so that all runs read the same
+// documents and either all or none of them hit errPqarrowStructBitmap.
+const deepSeed = 1
+
+// deepLength returns the length of an optional list: -1 for a null list
+// in one out of eight cases, otherwise up to max elements, which
+// includes empty lists.
+func deepLength(rnd *rand.Rand, max int) int {
+	if rnd.Intn(8) == 0 {
+		return -1
+	}
+	return rnd.Intn(max + 1)
+}
+
+// deepNull reports whether an optional element is null, which it is in
+// one out of eight cases.
+func deepNull(rnd *rand.Rand) bool {
+	return rnd.Intn(8) == 0
+}
+
+func generateDeepDocuments(n int) []deepDocument {
+	rnd := rand.New(rand.NewSource(deepSeed))
+
+	data := make([]deepDocument, n)
+	for i := range data {
+		doc := &data[i]
+		doc.ID = int64(i)
+
+		if l := deepLength(rnd, 4); l >= 0 {
+			doc.Matrix = make([][]*int64, l)
+		}
+		for j := range doc.Matrix {
+			if deepNull(rnd) {
+				continue
+			}
+			l := deepLength(rnd, 4)
+			if l < 0 {
+				continue
+			}
+			row := make([]*int64, l)
+			for k := range row {
+				if !deepNull(rnd) {
+					v := rnd.Int63n(1000)
+					row[k] = &v
+				}
+			}
+			doc.Matrix[j] = row
+		}
+
+		if l := deepLength(rnd, 3); l >= 0 {
+			doc.Sections = make([]*deepSection, l)
+		}
+		for j := range doc.Sections {
+			if deepNull(rnd) {
+				continue
+			}
+			s := &deepSection{}
+			if !deepNull(rnd) {
+				heading := fmt.Sprintf("section %d.%d", i, j)
+				s.Heading = &heading
+			}
+			if l := deepLength(rnd, 3); l >= 0 {
+				s.Paragraphs = make([]*deepParagraph, l)
+			}
+			for k := range s.Paragraphs {
+				if deepNull(rnd) {
+					continue
+				}
+				p := &deepParagraph{}
+				if l := deepLength(rnd, 4); l >= 0 {
+					p.Tags = make([]*string, l)
+				}
+				for t := range p.Tags {
+					if !deepNull(rnd) {
+						tag := deepTags[rnd.Intn(len(deepTags))]
+						p.Tags[t] = &tag
+					}
+				}
+				s.Paragraphs[k] = p
+			}
+			doc.Sections[j] = s
+		}
+	}
+	return data
+}
+
+// createDeepListObject returns the low-level representation of a list
+// with n elements. An empty list has to be written without the repeated
+// group, and a null element is a list entry without an element.
+func createDeepListObject(n int, elem func(i int) interface{}) map[string]interface{} {
+	if n == 0 {
+		return map[string]interface{}{}
+	}
+	list := make([]map[string]interface{}, n)
+	for i := range list {
+		list[i] = map[string]interface{}{}
+		if v := elem(i); v != nil {
+			list[i]["element"] = v
+		}
+	}
+	return map[string]interface{}{"list": list}
+}
+
+// createDeepObject converts a document into the map representation used
+// by parquet-go's low-level API.
+func createDeepObject(doc deepDocument) map[string]interface{} {
+	obj := map[string]interface{}{
+		"id": doc.ID,
+	}
+
+	if doc.Matrix != nil {
+		obj["matrix"] = createDeepListObject(len(doc.Matrix), func(i int) interface{} {
+			row := doc.Matrix[i]
+			if row == nil {
+				return nil
+			}
+			return createDeepListObject(len(row), func(j int) interface{} {
+				if row[j] == nil {
+					return nil
+				}
+				return *row[j]
+			})
+		})
+	}
+
+	if doc.Sections != nil {
+		obj["sections"] = createDeepListObject(len(doc.Sections), func(i int) interface{} {
+			s := doc.Sections[i]
+			if s == nil {
+				return nil
+			}
+			section := map[string]interface{}{}
+			if s.Heading != nil {
+				section["heading"] = []byte(*s.Heading)
+			}
+			if s.Paragraphs != nil {
+				section["paragraphs"] = createDeepListObject(len(s.Paragraphs), func(j int) interface{} {
+					p := s.Paragraphs[j]
+					if p == nil {
+						return nil
+					}
+					paragraph := map[string]interface{}{}
+					if p.Tags != nil {
+						paragraph["tags"] = createDeepListObject(len(p.Tags), func(k int) interface{} {
+							if p.Tags[k] == nil {
+								return nil
+							}
+							return []byte(*p.Tags[k])
+						})
+					}
+					return paragraph
+				})
+			}
+			return section
+		})
+	}
+
+	return obj
+}
+
+func deepArrowSchema() (*schema.GroupNode, error) {
+	row, err := schema.ListOf(schema.MustPrimitive(schema.NewPrimitiveNode("matrix", parquet3.Repetitions.Optional, parquet3.Types.Int64, 0, 0)), parquet3.Repetitions.Optional, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix, err := schema.ListOf(row, parquet3.Repetitions.Optional, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := schema.ListOf(schema.MustPrimitive(schema.NewPrimitiveNodeLogical("tags", parquet3.Repetitions.Optional, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)), parquet3.Repetitions.Optional, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	paragraph, err := schema.NewGroupNode("paragraphs", parquet3.Repetitions.Optional, schema.FieldList{tags}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	paragraphs, err := schema.ListOf(paragraph, parquet3.Repetitions.Optional, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := schema.NewGroupNode("sections", parquet3.Repetitions.Optional, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("heading", parquet3.Repetitions.Optional, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+		paragraphs,
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := schema.ListOf(section, parquet3.Repetitions.Optional, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.NewGroupNode("document", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+		matrix,
+		sections,
+	}, 0)
+}
+
+// deepPqarrowSchema is the arrow schema that pqarrow maps to deepSchema.
+// The elements of arrow lists are nullable.
+func deepPqarrowSchema() *arrow.Schema {
+	paragraph := arrow.StructOf(
+		arrow.Field{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+	)
+
+	section := arrow.StructOf(
+		arrow.Field{Name: "heading", Type: arrow.BinaryTypes.String, Nullable: true},
+		arrow.Field{Name: "paragraphs", Type: arrow.ListOf(paragraph), Nullable: true},
+	)
+
+	return arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "matrix", Type: arrow.ListOf(arrow.ListOf(arrow.PrimitiveTypes.Int64)), Nullable: true},
+		{Name: "sections", Type: arrow.ListOf(section), Nullable: true},
+	}, nil)
+}
+
+// deepSegmentioSchema builds deepSchema for segmentio. Struct tags can't
+// express a list of lists, and the columns of a Group are sorted by name,
+// which matches the order of deepSchema.
+func deepSegmentioSchema() *parquet4.Schema {
+	return parquet4.NewSchema("document", parquet4.Group{
+		"id":     parquet4.Int(64),
+		"matrix": parquet4.Optional(parquet4.List(parquet4.Optional(parquet4.List(parquet4.Optional(parquet4.Int(64)))))),
+		"sections": parquet4.Optional(parquet4.List(parquet4.Optional(parquet4.Group{
+			"heading": parquet4.Optional(parquet4.String()),
+			"paragraphs": parquet4.Optional(parquet4.List(parquet4.Optional(parquet4.Group{
+				"tags": parquet4.Optional(parquet4.List(parquet4.Optional(parquet4.String()))),
+			}))),
+		}))),
+	})
+}
+
+// appendSegmentioDeepRow appends the values of the shredded columns to
+// row, in the column order segmentio expects.
+func appendSegmentioDeepRow(row parquet4.Row, cols *deepColumns) parquet4.Row {
+	for i := range cols {
+		c := &cols[i]
+		valueIdx := 0
+		for j, def := range c.defLevels {
+			var v parquet4.Value
+			if def == deepLeaves[i].maxDef {
+				if deepLeaves[i].int64s {
+					v = parquet4.ValueOf(c.int64s[valueIdx])
+				} else {
+					v = parquet4.ValueOf([]byte(c.byteArrays[valueIdx]))
+				}
+				valueIdx++
+			}
+			row = append(row, v.Level(int(c.repLevels[j]), int(def), i))
+		}
+	}
+	return row
+}
+
+func BenchmarkDeepNestedWriting(b *testing.B) {
+	prefix := "deepwr_"
+
+	data := generateDeepDocuments(50000)
+	expected := shredDeepDocuments(data)
+
+	b.ResetTimer()
+
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		b.Skip("floor's reflection marshaller writes empty lists as null lists")
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(deepSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, doc := range data {
+					r := deepRecord(doc)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDeepFile(b, parquetFilename, expected, arrowDeepColumnReader(1024))
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(deepSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, doc := range data {
+					if err = fw.AddData(createDeepObject(doc)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDeepFile(b, parquetFilename, expected, arrowDeepColumnReader(1024))
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		b.Skip("xitongsys loses all values below a list element that is a list or a struct")
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := deepArrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						// arrow writes column by column, so the documents
+						// are shredded into values and levels first.
+						cols := shredDeepDocuments(data)
+
+						rg := pw.AppendRowGroup()
+
+						for i := range cols {
+							c := &cols[i]
+
+							col, err := rg.NextColumn()
+							if err != nil {
+								b.Fatalf("NextColumn failed: %v", err)
+							}
+
+							maxDef := deepLeaves[i].maxDef
+							levels := func(start, end int) (defLevels, repLevels []int16) {
+								if maxDef == 0 {
+									return nil, nil
+								}
+								return c.defLevels[start:end], c.repLevels[start:end]
+							}
+
+							switch col := col.(type) {
+							case *file.Int64ColumnChunkWriter:
+								err = writeLeveledBatches(c.defLevels, c.repLevels, maxDef, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+									defLevels, repLevels := levels(levelStart, levelEnd)
+									_, err := col.WriteBatch(c.int64s[valueStart:valueEnd], defLevels, repLevels)
+									return err
+								})
+							case *file.ByteArrayColumnChunkWriter:
+								err = writeLeveledBatches(c.defLevels, c.repLevels, maxDef, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+									defLevels, repLevels := levels(levelStart, levelEnd)
+									_, err := col.WriteBatch(c.byteArrays[valueStart:valueEnd], defLevels, repLevels)
+									return err
+								})
+							default:
+								b.Fatalf("unexpected %s column %T", deepLeaves[i].path, col)
+							}
+							if err != nil {
+								b.Fatalf("WriteBatch failed: %v", err)
+							}
+
+							col.Close()
+						}
+
+						defer rg.Close()
+					}()
+				}
+
+				b.StopTimer()
+				verifyDeepFile(b, filename, expected, arrowDeepColumnReader(1024))
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := deepPqarrowSchema()
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				var (
+					idBld         = bld.Field(0).(*array.Int64Builder)
+					matrixBld     = bld.Field(1).(*array.ListBuilder)
+					rowBld        = matrixBld.ValueBuilder().(*array.ListBuilder)
+					cellBld       = rowBld.ValueBuilder().(*array.Int64Builder)
+					sectionsBld   = bld.Field(2).(*array.ListBuilder)
+					sectionBld    = sectionsBld.ValueBuilder().(*array.StructBuilder)
+					headingBld    = sectionBld.FieldBuilder(0).(*array.StringBuilder)
+					paragraphsBld = sectionBld.FieldBuilder(1).(*array.ListBuilder)
+					paragraphBld  = paragraphsBld.ValueBuilder().(*array.StructBuilder)
+					tagsBld       = paragraphBld.FieldBuilder(0).(*array.ListBuilder)
+					tagBld        = tagsBld.ValueBuilder().(*array.StringBuilder)
+				)
+
+				// a null struct also appends nulls to its fields.
+				for _, doc := range data {
+					idBld.Append(doc.ID)
+
+					matrixBld.Append(doc.Matrix != nil)
+					for _, row := range doc.Matrix {
+						rowBld.Append(row != nil)
+						for _, v := range row {
+							if v == nil {
+								cellBld.AppendNull()
+							} else {
+								cellBld.Append(*v)
+							}
+						}
+					}
+
+					sectionsBld.Append(doc.Sections != nil)
+					for _, s := range doc.Sections {
+						if s == nil {
+							sectionBld.AppendNull()
+							continue
+						}
+						sectionBld.Append(true)
+
+						if s.Heading == nil {
+							headingBld.AppendNull()
+						} else {
+							headingBld.Append(*s.Heading)
+						}
+
+						paragraphsBld.Append(s.Paragraphs != nil)
+						for _, p := range s.Paragraphs {
+							if p == nil {
+								paragraphBld.AppendNull()
+								continue
+							}
+							paragraphBld.Append(true)
+
+							tagsBld.Append(p.Tags != nil)
+							for _, t := range p.Tags {
+								if t == nil {
+									tagBld.AppendNull()
+								} else {
+									tagBld.Append(*t)
+								}
+							}
+						}
+					}
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDeepFile(b, filename, expected, arrowDeepColumnReader(1024))
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, deepSegmentioSchema(), parquet4.Compression(&snappy.Codec{}))
+
+				// segmentio's struct reflection doesn't support
+				// lists of lists, so every document is shredded
+				// into a row of leveled values.
+				var (
+					cols deepColumns
+					row  parquet4.Row
+				)
+				for _, doc := range data {
+					cols.reset()
+					cols.add(doc)
+					row = appendSegmentioDeepRow(row[:0], &cols)
+					if err := wr.WriteRow(row); err != nil {
+						b.Fatalf("WriteRow failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyDeepFile(b, parquetFilename, expected, readSegmentioDeepColumns)
+	})
+}
+
+// deepRecord has the same layout as deepDocument, but brings its own
+// marshalling code instead of relying on reflection.
+type deepRecord deepDocument
+
+// marshalDeepList starts an optional list with n elements. An empty list
+// has to be written without the repeated group, so no list is returned
+// for it.
+func marshalDeepList(field interfaces.MarshalElement, n int) interfaces.MarshalList {
+	if n == 0 {
+		field.Group()
+		return nil
+	}
+	return field.List()
+}
+
+func (r *deepRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("id").SetInt64(r.ID)
+
+	if r.Matrix != nil {
+		matrix := marshalDeepList(obj.AddField("matrix"), len(r.Matrix))
+		for _, row := range r.Matrix {
+			elem := matrix.Add()
+			if row == nil {
+				continue
+			}
+			list := marshalDeepList(elem, len(row))
+			for _, v := range row {
+				elem := list.Add()
+				if v != nil {
+					elem.SetInt64(*v)
+				}
+			}
+		}
+	}
+
+	if r.Sections != nil {
+		sections := marshalDeepList(obj.AddField("sections"), len(r.Sections))
+		for _, s := range r.Sections {
+			elem := sections.Add()
+			if s == nil {
+				continue
+			}
+			section := elem.Group()
+			if s.Heading != nil {
+				section.AddField("heading").SetByteArray([]byte(*s.Heading))
+			}
+			if s.Paragraphs == nil {
+				continue
+			}
+			paragraphs := marshalDeepList(section.AddField("paragraphs"), len(s.Paragraphs))
+			for _, p := range s.Paragraphs {
+				elem := paragraphs.Add()
+				if p == nil {
+					continue
+				}
+				paragraph := elem.Group()
+				if p.Tags == nil {
+					continue
+				}
+				tags := marshalDeepList(paragraph.AddField("tags"), len(p.Tags))
+				for _, t := range p.Tags {
+					elem := tags.Add()
+					if t != nil {
+						elem.SetByteArray([]byte(*t))
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,573 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkFloat64Reading(b *testing.B) {
+	numRecords := 1000000
+
+	for _, gen := range floatGenerators {
+		gen := gen
+
+		b.Run(gen.name, func(b *testing.B) {
+			data := gen.generate(numRecords)
+			b.ResetTimer()
+
+			benchmarkFloat64Reading(b, data, "float64_"+gen.name+"_")
+		})
+	}
+}
+
+func benchmarkFloat64Reading(b *testing.B, data []float64, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(float64WritingSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, num := range data {
+		stu := myFloat64Record{
+			Foo: num,
+		}
+		if err = fw.Write(stu); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for {
+					_, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Foo float64 `parquet:"foo"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec reflectRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec myFloat64Record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Foo float64 `parquet:"name=foo, type=DOUBLE"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		type record struct {
+			Foo float64 `parquet:"foo"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				for {
+					var rec record
+					if err := r.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]float64, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.Float64ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert foo column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								if _, _, err := col.ReadBatch(int64(len(values)), values, nil, nil); err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						for _, v := range rec.Column(0).(*array.Float64).Float64Values() {
+							if v != data[row] {
+								b.Fatalf("row %d is %f, expected %f", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					for _, v := range chunk.(*array.Float64).Float64Values() {
+						if v != data[row] {
+							b.Fatalf("row %d is %f, expected %f", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myFloat64Record struct {
+	Foo float64 `parquet:"foo"`
+}
+
+func (r *myFloat64Record) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	f64, err := obj.GetField("foo").Float64()
+	if err != nil {
+		return err
+	}
+	r.Foo = f64
+	return nil
+}
+
+func BenchmarkFloat32Reading(b *testing.B) {
+	numRecords := 1000000
+
+	for _, gen := range floatGenerators {
+		gen := gen
+
+		b.Run(gen.name, func(b *testing.B) {
+			data := float64sToFloat32s(gen.generate(numRecords))
+			b.ResetTimer()
+
+			benchmarkFloat32Reading(b, data, "float32_"+gen.name+"_")
+		})
+	}
+}
+
+func benchmarkFloat32Reading(b *testing.B, data []float32, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(float32WritingSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, num := range data {
+		stu := myFloat32Record{
+			Foo: num,
+		}
+		if err = fw.Write(stu); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for {
+					_, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Foo float32 `parquet:"foo"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec reflectRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec myFloat32Record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Foo float32 `parquet:"name=foo, type=FLOAT"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		type record struct {
+			Foo float32 `parquet:"foo"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				for {
+					var rec record
+					if err := r.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]float32, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.Float32ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert foo column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								if _, _, err := col.ReadBatch(int64(len(values)), values, nil, nil); err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						for _, v := range rec.Column(0).(*array.Float32).Float32Values() {
+							if v != data[row] {
+								b.Fatalf("row %d is %f, expected %f", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					for _, v := range chunk.(*array.Float32).Float32Values() {
+						if v != data[row] {
+							b.Fatalf("row %d is %f, expected %f", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myFloat32Record struct {
+	Foo float32 `parquet:"foo"`
+}
+
+func (r *myFloat32Record) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	f32, err := obj.GetField("foo").Float32()
+	if err != nil {
+		return err
+	}
+	r.Foo = f32
+	return nil
+}
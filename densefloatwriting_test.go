@@ -0,0 +1,826 @@
+package benchmark_test
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// floatGenerators produce the different shapes of floating-point data that
+// the dense float benchmarks are run with.
+var floatGenerators = []struct {
+	name     string
+	generate func(n int) []float64
+}{
+	{name: "random", generate: generateRandomFloats},
+	{name: "timeseries", generate: generateTimeSeriesFloats},
+	{name: "low_precision", generate: generateLowPrecisionFloats},
+}
+
+func generateRandomFloats(n int) []float64 {
+	data := make([]float64, n)
+	for i := range data {
+		data[i] = rand.NormFloat64() * 1000
+	}
+	return data
+}
+
+// generateTimeSeriesFloats returns a smooth signal like the readings of a
+// sensor: a slow oscillation with a small amount of jitter.
+func generateTimeSeriesFloats(n int) []float64 {
+	data := make([]float64, n)
+	base := 20.0
+	for i := range data {
+		base += rand.NormFloat64() * 0.01
+		data[i] = base + 5*math.Sin(float64(i)/1000) + rand.NormFloat64()*0.001
+	}
+	return data
+}
+
+// generateLowPrecisionFloats returns values with one decimal digit from a
+// narrow range, so only a few hundred distinct values occur.
+func generateLowPrecisionFloats(n int) []float64 {
+	data := make([]float64, n)
+	for i := range data {
+		data[i] = float64(rand.Intn(500)) / 10
+	}
+	return data
+}
+
+func float64sToFloat32s(data []float64) []float32 {
+	res := make([]float32, len(data))
+	for i, f := range data {
+		res[i] = float32(f)
+	}
+	return res
+}
+
+func BenchmarkFloat64Writing(b *testing.B) {
+	numRecords := 1000000
+
+	for _, gen := range floatGenerators {
+		gen := gen
+
+		b.Run(gen.name, func(b *testing.B) {
+			prefix := "float64wr_" + gen.name + "_"
+
+			data := gen.generate(numRecords)
+
+			benchmarkFloat64Writing(b, data, prefix)
+		})
+	}
+}
+
+const float64WritingSchema = `message test {
+	required double foo;
+}`
+
+func benchmarkFloat64Writing(b *testing.B, data []float64, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(float64WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Foo float64 `parquet:"foo"`
+				}
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = fw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(float64WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, num := range data {
+					r := float64Record(num)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(float64WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, num := range data {
+					stu := map[string]interface{}{
+						"foo": num,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel_disabledict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "parquet_go_lowlevel_disabledict.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+				float64Store, err := goparquet.NewDoubleStore(parquet.Encoding_PLAIN, false, &goparquet.ColumnParameters{})
+				if err != nil {
+					b.Fatalf("NewDoubleStore failed: %v", err)
+				}
+				fw.AddColumn("foo", goparquet.NewDataColumn(float64Store, parquet.FieldRepetitionType_REQUIRED))
+
+				for _, num := range data {
+					stu := map[string]interface{}{
+						"foo": num,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plain", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plain.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo float64 `parquet:"name=foo, type=DOUBLE, encoding=PLAIN"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plaindict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plaindict.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo float64 `parquet:"name=foo, type=DOUBLE, encoding=PLAIN_DICTIONARY"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("foo", parquet3.Repetitions.Required, parquet3.Types.Double, 0, 0)),
+						}, 0)
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						fooCol, ok := col.(*file.Float64ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert foo column which is %T", col)
+						}
+
+						if err := writeBatches(len(data), batchSize, func(start, end int) error {
+							_, err := fooCol.WriteBatch(data[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						fooCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "foo", Type: arrow.PrimitiveTypes.Float64}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.Float64Builder).AppendValues(data, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_plain", func(b *testing.B) {
+		type record struct {
+			Foo float64 `parquet:"foo,plain"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_nodict.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_dict", func(b *testing.B) {
+		type record struct {
+			Foo float64 `parquet:"foo,dict"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_dict.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+}
+
+type float64Record float64
+
+func (r float64Record) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("foo").SetFloat64(float64(r))
+	return nil
+}
+
+func BenchmarkFloat32Writing(b *testing.B) {
+	numRecords := 1000000
+
+	for _, gen := range floatGenerators {
+		gen := gen
+
+		b.Run(gen.name, func(b *testing.B) {
+			prefix := "float32wr_" + gen.name + "_"
+
+			data := float64sToFloat32s(gen.generate(numRecords))
+
+			benchmarkFloat32Writing(b, data, prefix)
+		})
+	}
+}
+
+const float32WritingSchema = `message test {
+	required float foo;
+}`
+
+func benchmarkFloat32Writing(b *testing.B, data []float32, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(float32WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Foo float32 `parquet:"foo"`
+				}
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = fw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(float32WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, num := range data {
+					r := float32Record(num)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(float32WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, num := range data {
+					stu := map[string]interface{}{
+						"foo": num,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel_disabledict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "parquet_go_lowlevel_disabledict.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+				float32Store, err := goparquet.NewFloatStore(parquet.Encoding_PLAIN, false, &goparquet.ColumnParameters{})
+				if err != nil {
+					b.Fatalf("NewFloatStore failed: %v", err)
+				}
+				fw.AddColumn("foo", goparquet.NewDataColumn(float32Store, parquet.FieldRepetitionType_REQUIRED))
+
+				for _, num := range data {
+					stu := map[string]interface{}{
+						"foo": num,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plain", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plain.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo float32 `parquet:"name=foo, type=FLOAT, encoding=PLAIN"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plaindict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plaindict.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo float32 `parquet:"name=foo, type=FLOAT, encoding=PLAIN_DICTIONARY"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("foo", parquet3.Repetitions.Required, parquet3.Types.Float, 0, 0)),
+						}, 0)
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						fooCol, ok := col.(*file.Float32ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert foo column which is %T", col)
+						}
+
+						if err := writeBatches(len(data), batchSize, func(start, end int) error {
+							_, err := fooCol.WriteBatch(data[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						fooCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "foo", Type: arrow.PrimitiveTypes.Float32}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.Float32Builder).AppendValues(data, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_plain", func(b *testing.B) {
+		type record struct {
+			Foo float32 `parquet:"foo,plain"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_nodict.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_dict", func(b *testing.B) {
+		type record struct {
+			Foo float32 `parquet:"foo,dict"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_dict.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+}
+
+type float32Record float32
+
+func (r float32Record) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("foo").SetFloat32(float32(r))
+	return nil
+}
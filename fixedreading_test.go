@@ -0,0 +1,297 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkFixedLenByteArrayReading(b *testing.B) {
+	numRecords := 1000000
+
+	for _, width := range fixedWidths {
+		width := width
+
+		b.Run(width.name, func(b *testing.B) {
+			data := generateFixedLenData(numRecords, width)
+			b.ResetTimer()
+
+			benchmarkFixedLenByteArrayReading(b, data, width, "flba_"+width.name+"_")
+		})
+	}
+}
+
+func benchmarkFixedLenByteArrayReading(b *testing.B, data [][]byte, width fixedWidth, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(width.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, v := range data {
+		if err = fw.Write(fixedLenRecord(v)); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for {
+					_, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			ID []byte `parquet:"id"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec reflectRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec myFixedLenRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			ID string
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, width.xitongsysSchema(), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				for {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					_ = row[0].ByteArray()
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]parquet3.FixedLenByteArray, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.FixedLenByteArrayColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert id column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								if _, _, err := col.ReadBatch(int64(len(values)), values, nil, nil); err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						values := rec.Column(0).(*array.FixedSizeBinary)
+						for j := 0; j < values.Len(); j++ {
+							if v := values.Value(j); !bytes.Equal(v, data[row]) {
+								b.Fatalf("row %d is %x, expected %x", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					values := chunk.(*array.FixedSizeBinary)
+					for j := 0; j < values.Len(); j++ {
+						if v := values.Value(j); !bytes.Equal(v, data[row]) {
+							b.Fatalf("row %d is %x, expected %x", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myFixedLenRecord struct {
+	ID []byte
+}
+
+func (r *myFixedLenRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	id, err := obj.GetField("id").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.ID = id
+	return nil
+}
@@ -0,0 +1,455 @@
+package benchmark_test
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// fixedWidth describes a FIXED_LEN_BYTE_ARRAY column of a particular
+// width, optionally annotated with the UUID logical type.
+type fixedWidth struct {
+	name string
+	size int
+	uuid bool
+}
+
+var fixedWidths = []fixedWidth{
+	{name: "uuid", size: 16, uuid: true},
+	{name: "fixed_4", size: 4},
+	{name: "fixed_32", size: 32},
+}
+
+func (w fixedWidth) schemaDefinition() string {
+	annotation := ""
+	if w.uuid {
+		annotation = " (UUID)"
+	}
+	return fmt.Sprintf(`message test {
+	required fixed_len_byte_array(%d) id%s;
+}`, w.size, annotation)
+}
+
+func (w fixedWidth) columnParameters() *goparquet.ColumnParameters {
+	typeLength := int32(w.size)
+	params := &goparquet.ColumnParameters{
+		TypeLength: &typeLength,
+	}
+	if w.uuid {
+		params.LogicalType = parquet.NewLogicalType()
+		params.LogicalType.UUID = parquet.NewUUIDType()
+	}
+	return params
+}
+
+func (w fixedWidth) xitongsysSchema() string {
+	annotation := ""
+	if w.uuid {
+		annotation = ", logicaltype=UUID"
+	}
+	return fmt.Sprintf(`{
+	"Tag": "name=test, repetitiontype=REQUIRED",
+	"Fields": [
+		{"Tag": "name=id, inname=ID, type=FIXED_LEN_BYTE_ARRAY, length=%d%s, repetitiontype=REQUIRED"}
+	]
+}`, w.size, annotation)
+}
+
+func (w fixedWidth) arrowSchema() (*schema.GroupNode, error) {
+	var node schema.Node
+	if w.uuid {
+		node = schema.MustPrimitive(schema.NewPrimitiveNodeLogical("id", parquet3.Repetitions.Required, schema.UUIDLogicalType{}, parquet3.Types.FixedLenByteArray, w.size, 0))
+	} else {
+		node = schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.FixedLenByteArray, 0, int32(w.size)))
+	}
+	return schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{node}, 0)
+}
+
+func (w fixedWidth) segmentioSchema() *parquet4.Schema {
+	node := parquet4.Leaf(parquet4.FixedLenByteArrayType(w.size))
+	if w.uuid {
+		node = parquet4.UUID()
+	}
+	return parquet4.NewSchema("test", parquet4.Group{
+		"id": node,
+	})
+}
+
+// generateFixedLenData returns n random values of the given width. When
+// uuid is set, the values are formatted as version 4 UUIDs.
+func generateFixedLenData(n int, w fixedWidth) [][]byte {
+	data := make([][]byte, n)
+	for i := range data {
+		v := make([]byte, w.size)
+		rand.Read(v)
+		if w.uuid {
+			v[6] = (v[6] & 0x0f) | 0x40
+			v[8] = (v[8] & 0x3f) | 0x80
+		}
+		data[i] = v
+	}
+	return data
+}
+
+func BenchmarkFixedLenByteArrayWriting(b *testing.B) {
+	numRecords := 1000000
+
+	for _, width := range fixedWidths {
+		width := width
+
+		b.Run(width.name, func(b *testing.B) {
+			prefix := "flbawr_" + width.name + "_"
+
+			data := generateFixedLenData(numRecords, width)
+
+			benchmarkFixedLenByteArrayWriting(b, data, width, prefix)
+		})
+	}
+}
+
+func benchmarkFixedLenByteArrayWriting(b *testing.B, data [][]byte, width fixedWidth, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(width.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					ID []byte `parquet:"id"`
+				}
+
+				for _, v := range data {
+					stu := record{
+						ID: v,
+					}
+					if err = fw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(width.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, v := range data {
+					if err = fw.Write(fixedLenRecord(v)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(width.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, v := range data {
+					stu := map[string]interface{}{
+						"id": v,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel_disabledict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "parquet_go_lowlevel_disabledict.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+				fixedStore, err := goparquet.NewFixedByteArrayStore(parquet.Encoding_PLAIN, false, width.columnParameters())
+				if err != nil {
+					b.Fatalf("NewFixedByteArrayStore failed: %v", err)
+				}
+				fw.AddColumn("id", goparquet.NewDataColumn(fixedStore, parquet.FieldRepetitionType_REQUIRED))
+
+				for _, v := range data {
+					stu := map[string]interface{}{
+						"id": v,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					ID string
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, width.xitongsysSchema(), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, v := range data {
+					stu := record{
+						ID: string(v),
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		values := make([]parquet3.FixedLenByteArray, len(data))
+		for i, v := range data {
+			values[i] = v
+		}
+
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := width.arrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						idCol, ok := col.(*file.FixedLenByteArrayColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert id column which is %T", col)
+						}
+
+						if err := writeBatches(len(values), batchSize, func(start, end int) error {
+							_, err := idCol.WriteBatch(values[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						idCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
+
+	// arrow has no UUID type, so pqarrow writes UUIDs as plain fixed size
+	// binary values without the UUID annotation.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "id", Type: &arrow.FixedSizeBinaryType{ByteWidth: width.size}}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.FixedSizeBinaryBuilder).AppendValues(data, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, width.segmentioSchema(), parquet4.Compression(&snappy.Codec{}))
+
+				row := make(parquet4.Row, 1)
+
+				for _, v := range data {
+					row[0] = parquet4.FixedLenByteArray.Value(v).Level(0, 0, 0)
+					if err := wr.WriteRow(row); err != nil {
+						b.Fatalf("WriteRow failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	if width.size != 16 {
+		return
+	}
+
+	// segmentio maps [16]byte struct fields to FIXED_LEN_BYTE_ARRAY(16).
+	// The uuid tag only validates the field type, so the column isn't
+	// annotated with the UUID logical type.
+	b.Run("segmentio_parquet_go_array", func(b *testing.B) {
+		type record struct {
+			ID [16]byte `parquet:"id,uuid"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_array.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, v := range data {
+					rec := &record{}
+					copy(rec.ID[:], v)
+					if err := wr.Write(rec); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+}
+
+type fixedLenRecord []byte
+
+func (r fixedLenRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("id").SetByteArray([]byte(r))
+	return nil
+}
@@ -0,0 +1,442 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// Definition levels of the sparseFloat64Schema leaf column: the list is
+// empty, the element is null, or the element is set.
+const (
+	sparseDefEmptyList   = 0
+	sparseDefNullElement = 1
+	sparseDefValue       = 2
+)
+
+func BenchmarkSparseFloat64Reading(b *testing.B) {
+	testData := generateSparseFloat64Data(100000)
+
+	schemaDef, err := parquetschema.ParseSchemaDefinition(sparseFloat64Schema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := "float64rd_testdata.parquet"
+
+	w, err := os.Create(parquetFilename)
+	if err != nil {
+		b.Fatalf("Create failed: %v", err)
+	}
+
+	fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+	for _, line := range testData {
+		if err = fw.AddData(createLineObject(line)); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+	w.Close()
+
+	check := func(b *testing.B, row int, line []*float64) {
+		if row >= len(testData) {
+			b.Fatalf("read more than the %d rows that were written", len(testData))
+		}
+		if err := compareSparseLines(line, testData[row]); err != nil {
+			b.Fatalf("row %d: %v", row, err)
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for row := 0; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					line, err := sparseLineFromRow(values)
+					if err != nil {
+						b.Fatalf("row %d: %v", row, err)
+					}
+					check(b, row, line)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		b.Skip("floor's reflection unmarshaller fails on null list elements and empty lists")
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var rec mySparseFloat64Record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, rec.Data)
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Data []*float64 `parquet:"name=data, type=LIST, convertedtype=LIST, valuetype=DOUBLE"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					for _, r := range rec {
+						check(b, row, r.Data)
+						row++
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				for n := 0; ; n++ {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+
+					var line []*float64
+					for _, v := range row {
+						switch v.DefinitionLevel() {
+						case sparseDefEmptyList:
+						case sparseDefNullElement:
+							line = append(line, nil)
+						case sparseDefValue:
+							x := v.Double()
+							line = append(line, &x)
+						default:
+							b.Fatalf("row %d: unexpected definition level %d", n, v.DefinitionLevel())
+						}
+					}
+					check(b, n, line)
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]float64, batchSize)
+				defLevels := make([]int16, batchSize)
+				repLevels := make([]int16, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						var line []*float64
+						row := -1
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.Float64ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert element column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								numLevels, _, err := col.ReadBatch(int64(len(values)), values, defLevels, repLevels)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+
+								// values only holds the set elements, so it is
+								// consumed separately from the levels.
+								valueIdx := 0
+								for idx := 0; idx < int(numLevels); idx++ {
+									if repLevels[idx] == 0 {
+										if row >= 0 {
+											check(b, row, line)
+										}
+										line = nil
+										row++
+									}
+
+									switch defLevels[idx] {
+									case sparseDefEmptyList:
+									case sparseDefNullElement:
+										line = append(line, nil)
+									case sparseDefValue:
+										x := values[valueIdx]
+										valueIdx++
+										line = append(line, &x)
+									default:
+										b.Fatalf("row %d: unexpected definition level %d", row, defLevels[idx])
+									}
+								}
+							}
+						}
+
+						if row >= 0 {
+							check(b, row, line)
+						}
+						if row+1 != len(testData) {
+							b.Fatalf("read %d rows, expected %d", row+1, len(testData))
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						lists := rec.Column(0).(*array.List)
+						elems := lists.ListValues().(*array.Float64)
+						for j := 0; j < lists.Len(); j++ {
+							var line []*float64
+							start, end := listRange(lists, j)
+							for idx := start; idx < end; idx++ {
+								if elems.IsNull(idx) {
+									line = append(line, nil)
+									continue
+								}
+								x := elems.Value(idx)
+								line = append(line, &x)
+							}
+							check(b, row, line)
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(testData) {
+						b.Fatalf("read %d rows, expected %d", row, len(testData))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					lists := chunk.(*array.List)
+					elems := lists.ListValues().(*array.Float64)
+					for j := 0; j < lists.Len(); j++ {
+						var line []*float64
+						start, end := listRange(lists, j)
+						for idx := start; idx < end; idx++ {
+							if elems.IsNull(idx) {
+								line = append(line, nil)
+								continue
+							}
+							x := elems.Value(idx)
+							line = append(line, &x)
+						}
+						check(b, row, line)
+						row++
+					}
+				}
+				if row != len(testData) {
+					b.Fatalf("read %d rows, expected %d", row, len(testData))
+				}
+			}()
+		}
+	})
+}
+
+// compareSparseLines returns an error describing the first difference
+// between a decoded line and the line that was written.
+func compareSparseLines(got, want []*float64) error {
+	if len(got) != len(want) {
+		return fmt.Errorf("got %d elements, expected %d", len(got), len(want))
+	}
+	for idx := range want {
+		switch {
+		case want[idx] == nil && got[idx] == nil:
+		case want[idx] == nil:
+			return fmt.Errorf("element %d is %f, expected null", idx, *got[idx])
+		case got[idx] == nil:
+			return fmt.Errorf("element %d is null, expected %f", idx, *want[idx])
+		case *got[idx] != *want[idx]:
+			return fmt.Errorf("element %d is %f, expected %f", idx, *got[idx], *want[idx])
+		}
+	}
+	return nil
+}
+
+// sparseLineFromRow is the inverse of createLineObject.
+func sparseLineFromRow(row map[string]interface{}) ([]*float64, error) {
+	data, ok := row["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected data %T", row["data"])
+	}
+
+	list, ok := data["list"]
+	if !ok {
+		return nil, nil
+	}
+
+	elems, ok := list.([]map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected list %T", list)
+	}
+
+	line := make([]*float64, len(elems))
+	for idx, elem := range elems {
+		v, ok := elem["element"]
+		if !ok {
+			continue
+		}
+		x, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected element %T", v)
+		}
+		line[idx] = &x
+	}
+
+	return line, nil
+}
+
+type mySparseFloat64Record struct {
+	Data []*float64
+}
+
+func (r *mySparseFloat64Record) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	data, err := obj.GetField("data").Group()
+	if err != nil {
+		return err
+	}
+
+	// an empty list comes without the repeated group.
+	if data.GetField("list").Error() != nil {
+		r.Data = nil
+		return nil
+	}
+
+	list, err := obj.GetField("data").List()
+	if err != nil {
+		return err
+	}
+
+	r.Data = r.Data[:0]
+	for list.Next() {
+		// Value only fails for null elements as the iterator can't run
+		// past the end of the list here.
+		elem, err := list.Value()
+		if err != nil {
+			r.Data = append(r.Data, nil)
+			continue
+		}
+		x, err := elem.Float64()
+		if err != nil {
+			return err
+		}
+		r.Data = append(r.Data, &x)
+	}
+
+	return nil
+}
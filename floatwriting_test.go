@@ -5,6 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
 	goparquet "github.com/fraugster/parquet-go"
 	"github.com/fraugster/parquet-go/parquet"
 	"github.com/fraugster/parquet-go/parquetschema"
@@ -23,19 +26,7 @@ const sparseFloat64Schema = `message msg {
 }`
 
 func BenchmarkSparseFloat64Writing(b *testing.B) {
-	testData := [][]*float64{}
-
-	for i := 0; i < 100000; i++ {
-		lineSize := rand.Intn(20)
-		line := make([]*float64, lineSize)
-		for idx := range line {
-			if idx == rand.Intn(20) {
-				x := rand.Float64()
-				line[idx] = &x
-			}
-		}
-		testData = append(testData, line)
-	}
+	testData := generateSparseFloat64Data(100000)
 
 	b.ResetTimer()
 
@@ -111,6 +102,40 @@ func BenchmarkSparseFloat64Writing(b *testing.B) {
 		}
 	})
 
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := "float64wr_apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "data", Type: arrow.ListOf(arrow.PrimitiveTypes.Float64)}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				listBld := bld.Field(0).(*array.ListBuilder)
+				valueBld := listBld.ValueBuilder().(*array.Float64Builder)
+
+				for _, line := range testData {
+					listBld.Append(true)
+					for _, v := range line {
+						if v == nil {
+							valueBld.AppendNull()
+						} else {
+							valueBld.Append(*v)
+						}
+					}
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
 	b.Run("segmentio_parquet_go", func(b *testing.B) {
 		defer func() {
 			if err := recover(); err != nil {
@@ -150,7 +175,35 @@ func BenchmarkSparseFloat64Writing(b *testing.B) {
 	})
 }
 
+// generateSparseFloat64Data returns n lines of up to 19 elements, most of
+// which are null. About one in twenty lines is empty.
+func generateSparseFloat64Data(n int) [][]*float64 {
+	testData := [][]*float64{}
+
+	for i := 0; i < n; i++ {
+		lineSize := rand.Intn(20)
+		line := make([]*float64, lineSize)
+		for idx := range line {
+			if idx == rand.Intn(20) {
+				x := rand.Float64()
+				line[idx] = &x
+			}
+		}
+		testData = append(testData, line)
+	}
+
+	return testData
+}
+
 func createLineObject(line []*float64) map[string]interface{} {
+	// an empty list needs to be written without the repeated group;
+	// an empty "list" slice ends up as a list with a single null element.
+	if len(line) == 0 {
+		return map[string]interface{}{
+			"data": map[string]interface{}{},
+		}
+	}
+
 	list := []map[string]interface{}{}
 
 	for _, fp := range line {
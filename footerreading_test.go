@@ -0,0 +1,424 @@
+package benchmark_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/metadata"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	goparquet "github.com/fraugster/parquet-go"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// footerSizes are the shapes of the files whose footers are read. The
+// footer grows with the number of column chunks, i.e. columns times row
+// groups.
+var footerSizes = []struct {
+	name         string
+	numColumns   int
+	numRowGroups int
+}{
+	{name: "small", numColumns: 5, numRowGroups: 1},
+	{name: "large", numColumns: 200, numRowGroups: 500},
+}
+
+const (
+	footerRowsPerRowGroup = 10
+	footerKeyValues       = 16
+)
+
+func footerKey(i int) string {
+	return fmt.Sprintf("key%02d", i)
+}
+
+func footerValue(i int) string {
+	return fmt.Sprintf("value of key %02d", i)
+}
+
+// writeFooterFile writes the table with apache arrow, whose statistics all
+// libraries can read, in row groups of footerRowsPerRowGroup rows, and
+// adds footerKeyValues key/value metadata entries.
+func writeFooterFile(filename string, t wideTable) error {
+	w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	sc, err := t.arrowSchema()
+	if err != nil {
+		return err
+	}
+
+	kv := metadata.NewKeyValueMetadata()
+	for i := 0; i < footerKeyValues; i++ {
+		if err := kv.Append(footerKey(i), footerValue(i)); err != nil {
+			return err
+		}
+	}
+
+	pw := file.NewParquetWriter(w, sc,
+		file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))),
+		file.WithWriteMetadata(kv),
+	)
+
+	for start := 0; start < len(t.rows); start += footerRowsPerRowGroup {
+		rows := t.rows[start:]
+		if len(rows) > footerRowsPerRowGroup {
+			rows = rows[:footerRowsPerRowGroup]
+		}
+
+		rg := pw.AppendRowGroup()
+
+		for c := 0; c < t.numColumns; c++ {
+			col, err := rg.NextColumn()
+			if err != nil {
+				return err
+			}
+
+			switch col := col.(type) {
+			case *file.Int32ColumnChunkWriter:
+				values := make([]int32, len(rows))
+				for i, row := range rows {
+					values[i] = row[c].(int32)
+				}
+				_, err = col.WriteBatch(values, nil, nil)
+			case *file.Int64ColumnChunkWriter:
+				values := make([]int64, len(rows))
+				for i, row := range rows {
+					values[i] = row[c].(int64)
+				}
+				_, err = col.WriteBatch(values, nil, nil)
+			case *file.Float64ColumnChunkWriter:
+				values := make([]float64, len(rows))
+				for i, row := range rows {
+					values[i] = row[c].(float64)
+				}
+				_, err = col.WriteBatch(values, nil, nil)
+			case *file.BooleanColumnChunkWriter:
+				values := make([]bool, len(rows))
+				for i, row := range rows {
+					values[i] = row[c].(bool)
+				}
+				_, err = col.WriteBatch(values, nil, nil)
+			case *file.ByteArrayColumnChunkWriter:
+				values := make([]parquet3.ByteArray, len(rows))
+				for i, row := range rows {
+					values[i] = parquet3.ByteArray(row[c].(string))
+				}
+				_, err = col.WriteBatch(values, nil, nil)
+			default:
+				return fmt.Errorf("unexpected column writer %T", col)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := col.Close(); err != nil {
+				return err
+			}
+		}
+
+		if err := rg.Close(); err != nil {
+			return err
+		}
+	}
+
+	return pw.Close()
+}
+
+func BenchmarkFooterReading(b *testing.B) {
+	for _, size := range footerSizes {
+		size := size
+
+		b.Run(size.name, func(b *testing.B) {
+			t := generateWideTable(size.numColumns, size.numRowGroups*footerRowsPerRowGroup)
+
+			parquetFilename := fmt.Sprintf("footerrd_%s_testdata.parquet", size.name)
+
+			if err := writeFooterFile(parquetFilename, t); err != nil {
+				b.Fatalf("Writing %s failed: %v", parquetFilename, err)
+			}
+
+			b.ResetTimer()
+
+			benchmarkFooterReading(b, parquetFilename, t, size.numRowGroups)
+		})
+	}
+}
+
+// benchmarkFooterReading opens a file and reads its schema, row count, the
+// min/max statistics of all column chunks and a key/value metadata entry,
+// without reading any data pages. The bytes read show whether a library
+// reads more than the footer to do so.
+func benchmarkFooterReading(b *testing.B, parquetFilename string, t wideTable, numRowGroups int) {
+	footerSize, err := parquetFooterSize(parquetFilename)
+	if err != nil {
+		b.Fatalf("Reading footer size failed: %v", err)
+	}
+
+	// the last key/value entry is looked up, so that libraries that
+	// search the entries linearly have to go through all of them.
+	key, value := footerKey(footerKeyValues-1), footerValue(footerKeyValues-1)
+
+	// statsNotRead is passed as the number of column chunks with
+	// statistics by libraries that can't read the statistics.
+	const statsNotRead = -1
+
+	check := func(b *testing.B, numRows int64, numColumns, statsChunks int, v string) {
+		if numRows != int64(len(t.rows)) {
+			b.Fatalf("file has %d rows, expected %d", numRows, len(t.rows))
+		}
+		if numColumns != t.numColumns {
+			b.Fatalf("file has %d columns, expected %d", numColumns, t.numColumns)
+		}
+		if statsChunks != statsNotRead && statsChunks != t.numColumns*numRowGroups {
+			b.Fatalf("file has %d column chunks with statistics, expected %d", statsChunks, t.numColumns*numRowGroups)
+		}
+		if v != value {
+			b.Fatalf("metadata %s is %q, expected %q", key, v, value)
+		}
+	}
+
+	// report also reports whether the statistics were read, as the
+	// libraries that skip them do less work.
+	report := func(b *testing.B, bytesRead int64, statsRead bool) {
+		b.ReportMetric(float64(bytesRead)/float64(b.N), "bytes-read/op")
+		b.ReportMetric(float64(footerSize), "footer-bytes")
+		if statsRead {
+			b.ReportMetric(1, "stats-read")
+		} else {
+			b.ReportMetric(0, "stats-read")
+		}
+	}
+
+	// floor opens files through the low-level reader, so it isn't
+	// benchmarked separately.
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				meta, err := goparquet.ReadFileMetaData(f, false)
+				if err != nil {
+					b.Fatalf("Reading file metadata failed: %v", err)
+				}
+
+				statsChunks := 0
+				for _, rg := range meta.RowGroups {
+					for _, col := range rg.Columns {
+						if stats := col.MetaData.Statistics; stats != nil && stats.MinValue != nil && stats.MaxValue != nil {
+							statsChunks++
+						}
+					}
+				}
+
+				var v string
+				for _, kv := range meta.KeyValueMetadata {
+					if kv.Key == key && kv.Value != nil {
+						v = *kv.Value
+					}
+				}
+
+				// the first schema element is the root of the schema.
+				check(b, meta.NumRows, len(meta.Schema)-1, statsChunks, v)
+			}()
+		}
+
+		report(b, bytesRead, true)
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+				defer f.Close()
+
+				pr, err := reader.NewParquetReader(f, nil, 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				statsChunks := 0
+				for _, rg := range pr.Footer.RowGroups {
+					for _, col := range rg.Columns {
+						if stats := col.MetaData.Statistics; stats != nil && stats.MinValue != nil && stats.MaxValue != nil {
+							statsChunks++
+						}
+					}
+				}
+
+				var v string
+				for _, kv := range pr.Footer.KeyValueMetadata {
+					if kv.Key == key && kv.Value != nil {
+						v = *kv.Value
+					}
+				}
+
+				check(b, pr.GetNumRows(), len(pr.SchemaHandler.ValueColumns), statsChunks, v)
+				pr.ReadStop()
+			}()
+		}
+
+		report(b, bytesRead, true)
+	})
+
+	// segmentio doesn't expose row group statistics, so it only reads the
+	// rest of the metadata and reports stats-read as 0. Page indexes and bloom filters are skipped, as
+	// they aren't part of the footer.
+	b.Run("segmentio", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				stat, err := f.Stat()
+				if err != nil {
+					b.Fatalf("Stat failed: %v", err)
+				}
+
+				pf, err := parquet4.OpenFile(f, stat.Size(), &parquet4.FileConfig{
+					SkipPageIndex:    true,
+					SkipBloomFilters: true,
+				})
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				v, _ := pf.Lookup(key)
+
+				check(b, pf.NumRows(), len(pf.Schema().Fields()), statsNotRead, v)
+			}()
+		}
+
+		report(b, bytesRead, false)
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := file.NewParquetReader(f)
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				meta := r.MetaData()
+
+				statsChunks, err := arrowStatsChunks(meta)
+				if err != nil {
+					b.Fatalf("Reading statistics failed: %v", err)
+				}
+
+				var v string
+				if value := meta.KeyValueMetadata().FindValue(key); value != nil {
+					v = *value
+				}
+
+				check(b, r.NumRows(), meta.Schema.NumColumns(), statsChunks, v)
+			}()
+		}
+
+		report(b, bytesRead, true)
+	})
+
+	// pqarrow converts the parquet schema into an arrow schema, but in
+	// arrow v8 that schema doesn't carry the key/value metadata, so it is
+	// looked up in the underlying file reader like the statistics.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := file.NewParquetReader(f)
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+				if err != nil {
+					b.Fatalf("Creating pqarrow reader failed: %v", err)
+				}
+
+				sc, err := fr.Schema()
+				if err != nil {
+					b.Fatalf("Converting schema failed: %v", err)
+				}
+
+				meta := fr.ParquetReader().MetaData()
+
+				statsChunks, err := arrowStatsChunks(meta)
+				if err != nil {
+					b.Fatalf("Reading statistics failed: %v", err)
+				}
+
+				var v string
+				if value := meta.KeyValueMetadata().FindValue(key); value != nil {
+					v = *value
+				}
+
+				check(b, r.NumRows(), len(sc.Fields()), statsChunks, v)
+			}()
+		}
+
+		report(b, bytesRead, true)
+	})
+}
+
+// arrowStatsChunks returns the number of column chunks in the file that
+// have min/max statistics.
+func arrowStatsChunks(meta *metadata.FileMetaData) (int, error) {
+	statsChunks := 0
+	for rg := 0; rg < len(meta.RowGroups); rg++ {
+		rgMeta := meta.RowGroup(rg)
+		for c := 0; c < rgMeta.NumColumns(); c++ {
+			cc, err := rgMeta.ColumnChunk(c)
+			if err != nil {
+				return 0, err
+			}
+			stats, err := cc.Statistics()
+			if err != nil {
+				return 0, err
+			}
+			if stats != nil && stats.HasMinMax() {
+				statsChunks++
+			}
+		}
+	}
+	return statsChunks, nil
+}
@@ -0,0 +1,596 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkInt64Reading(b *testing.B) {
+	numRecords := 1000000
+	data := make([]int64, numRecords)
+
+	b.Run("high_card", func(b *testing.B) {
+		for i := range data {
+			data[i] = rand.Int63()
+		}
+		b.ResetTimer()
+
+		benchmarkInt64Reading(b, data, "int64_high_card_")
+	})
+
+	b.Run("low_card", func(b *testing.B) {
+		cardinality := int64(1328)
+
+		for i := range data {
+			data[i] = rand.Int63n(cardinality)
+		}
+		b.ResetTimer()
+
+		benchmarkInt64Reading(b, data, "int64_low_card_")
+	})
+
+	b.Run("event_time", func(b *testing.B) {
+		for i, ts := range generateEventTimes(numRecords) {
+			data[i] = ts.UnixNano()
+		}
+		b.ResetTimer()
+
+		benchmarkInt64Reading(b, data, "int64_event_time_")
+	})
+}
+
+func benchmarkInt64Reading(b *testing.B, data []int64, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(int64WritingSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, num := range data {
+		stu := myInt64Record{
+			Foo: num,
+		}
+		if err = fw.Write(stu); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for {
+					_, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Foo int64 `parquet:"foo"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec reflectRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec myInt64Record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Foo int64 `parquet:"name=foo, type=INT64"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		type record struct {
+			Foo int64 `parquet:"foo"`
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				for {
+					var rec record
+					if err := r.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]int64, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.Int64ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert foo column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								if _, _, err := col.ReadBatch(int64(len(values)), values, nil, nil); err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						for _, v := range rec.Column(0).(*array.Int64).Int64Values() {
+							if v != data[row] {
+								b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					for _, v := range chunk.(*array.Int64).Int64Values() {
+						if v != data[row] {
+							b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myInt64Record struct {
+	Foo int64 `parquet:"foo"`
+}
+
+func (r *myInt64Record) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	i64, err := obj.GetField("foo").Int64()
+	if err != nil {
+		return err
+	}
+	r.Foo = i64
+	return nil
+}
+
+func BenchmarkTimestampReading(b *testing.B) {
+	numRecords := 1000000
+
+	eventTimes := generateEventTimes(numRecords)
+
+	for _, unit := range timestampUnits {
+		unit := unit
+
+		b.Run(unit.name, func(b *testing.B) {
+			data := make([]int64, numRecords)
+			for i, ts := range eventTimes {
+				data[i] = ts.UnixNano() / int64(unit.duration)
+			}
+			b.ResetTimer()
+
+			benchmarkTimestampReading(b, data, unit, "ts_"+unit.name+"_")
+		})
+	}
+}
+
+func benchmarkTimestampReading(b *testing.B, data []int64, unit timestampUnit, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(unit.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, ts := range data {
+		r := timestampRecord(ts)
+		if err = fw.Write(&r); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for {
+					_, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type reflectRecord struct {
+			Ts time.Time `parquet:"ts"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec reflectRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for r.Next() {
+					var rec myTimestampRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Ts int64
+		}
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, unit.xitongsysSchema(), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				for {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					_ = time.Unix(0, row[0].Int64()*int64(unit.duration))
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]int64, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.Int64ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert ts column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								if _, _, err := col.ReadBatch(int64(len(values)), values, nil, nil); err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						for _, v := range rec.Column(0).(*array.Timestamp).TimestampValues() {
+							if int64(v) != data[row] {
+								b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					for _, v := range chunk.(*array.Timestamp).TimestampValues() {
+						if int64(v) != data[row] {
+							b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+type myTimestampRecord struct {
+	Ts int64
+}
+
+func (r *myTimestampRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	i64, err := obj.GetField("ts").Int64()
+	if err != nil {
+		return err
+	}
+	r.Ts = i64
+	return nil
+}
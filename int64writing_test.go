@@ -0,0 +1,795 @@
+package benchmark_test
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func BenchmarkInt64Writing(b *testing.B) {
+	numRecords := 1000000
+
+	b.Run("high_card", func(b *testing.B) {
+		prefix := "int64wr_highcard_"
+
+		data := make([]int64, numRecords)
+
+		for i := range data {
+			data[i] = rand.Int63()
+		}
+
+		benchmarkInt64Writing(b, data, prefix)
+	})
+
+	b.Run("low_card", func(b *testing.B) {
+		prefix := "int64wr_lowcard_"
+		cardinality := int64(1516)
+
+		data := make([]int64, numRecords)
+
+		for i := range data {
+			data[i] = rand.Int63n(cardinality)
+		}
+
+		benchmarkInt64Writing(b, data, prefix)
+	})
+
+	b.Run("event_time", func(b *testing.B) {
+		prefix := "int64wr_eventtime_"
+
+		data := make([]int64, numRecords)
+
+		for i, ts := range generateEventTimes(numRecords) {
+			data[i] = ts.UnixNano()
+		}
+
+		benchmarkInt64Writing(b, data, prefix)
+	})
+}
+
+const int64WritingSchema = `message test {
+	required int64 foo;
+}`
+
+func benchmarkInt64Writing(b *testing.B, data []int64, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(int64WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Foo int64 `parquet:"foo"`
+				}
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = fw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(int64WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, num := range data {
+					r := int64Record(num)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(int64WritingSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, num := range data {
+					stu := map[string]interface{}{
+						"foo": num,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel_disabledict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "parquet_go_lowlevel_disabledict.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+				int64Store, err := goparquet.NewInt64Store(parquet.Encoding_PLAIN, false, &goparquet.ColumnParameters{})
+				if err != nil {
+					b.Fatalf("NewInt64Store failed: %v", err)
+				}
+				fw.AddColumn("foo", goparquet.NewDataColumn(int64Store, parquet.FieldRepetitionType_REQUIRED))
+
+				for _, num := range data {
+					stu := map[string]interface{}{
+						"foo": num,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plain", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plain.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo int64 `parquet:"name=foo, type=INT64, encoding=PLAIN"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go_plaindict", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go_plaindict.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Foo int64 `parquet:"name=foo, type=INT64, encoding=PLAIN_DICTIONARY"`
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(record), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, num := range data {
+					stu := record{
+						Foo: num,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("foo", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+						}, 0)
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						fooCol, ok := col.(*file.Int64ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert foo column which is %T", col)
+						}
+
+						if err := writeBatches(len(data), batchSize, func(start, end int) error {
+							_, err := fooCol.WriteBatch(data[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						fooCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{{Name: "foo", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.Int64Builder).AppendValues(data, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_plain", func(b *testing.B) {
+		type record struct {
+			Foo int64 `parquet:"foo,plain"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_nodict.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_dict", func(b *testing.B) {
+		type record struct {
+			Foo int64 `parquet:"foo,dict"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_dict.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go_delta", func(b *testing.B) {
+		type record struct {
+			Foo int64 `parquet:"foo,delta"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio_delta.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(record)), parquet4.Compression(&snappy.Codec{}))
+
+				for _, num := range data {
+					if err := wr.Write(&record{
+						Foo: num,
+					}); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+}
+
+type int64Record int64
+
+func (r int64Record) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("foo").SetInt64(int64(r))
+	return nil
+}
+
+// timestampUnit describes a TIMESTAMP logical type unit in the terms of
+// each of the libraries under test.
+type timestampUnit struct {
+	name      string
+	duration  time.Duration
+	fraugster string
+	arrow     schema.TimeUnitType
+	pqarrow   arrow.TimeUnit
+	segmentio parquet4.TimeUnit
+}
+
+var timestampUnits = []timestampUnit{
+	{name: "millis", duration: time.Millisecond, fraugster: "MILLIS", arrow: schema.TimeUnitMillis, pqarrow: arrow.Millisecond, segmentio: parquet4.Millisecond},
+	{name: "micros", duration: time.Microsecond, fraugster: "MICROS", arrow: schema.TimeUnitMicros, pqarrow: arrow.Microsecond, segmentio: parquet4.Microsecond},
+	{name: "nanos", duration: time.Nanosecond, fraugster: "NANOS", arrow: schema.TimeUnitNanos, pqarrow: arrow.Nanosecond, segmentio: parquet4.Nanosecond},
+}
+
+func (u timestampUnit) schemaDefinition() string {
+	return fmt.Sprintf(`message test {
+	required int64 ts (TIMESTAMP(%s, true));
+}`, u.fraugster)
+}
+
+func (u timestampUnit) xitongsysSchema() string {
+	return fmt.Sprintf(`{
+	"Tag": "name=test, repetitiontype=REQUIRED",
+	"Fields": [
+		{"Tag": "name=ts, inname=Ts, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=%s, repetitiontype=REQUIRED"}
+	]
+}`, u.fraugster)
+}
+
+func (u timestampUnit) arrowSchema() (*schema.GroupNode, error) {
+	return schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("ts", parquet3.Repetitions.Required, schema.NewTimestampLogicalType(true, u.arrow), parquet3.Types.Int64, 0, 0)),
+	}, 0)
+}
+
+// pqarrowSchema returns the arrow schema that pqarrow maps to the same
+// parquet schema. Timestamps with a time zone are adjusted to UTC.
+func (u timestampUnit) pqarrowSchema() *arrow.Schema {
+	return arrow.NewSchema([]arrow.Field{{Name: "ts", Type: &arrow.TimestampType{Unit: u.pqarrow, TimeZone: "UTC"}}}, nil)
+}
+
+func (u timestampUnit) segmentioSchema() *parquet4.Schema {
+	return parquet4.NewSchema("test", parquet4.Group{
+		"ts": parquet4.Timestamp(u.segmentio),
+	})
+}
+
+// generateEventTimes returns monotonically increasing timestamps with
+// irregular gaps between them, like the event times of a log stream.
+func generateEventTimes(n int) []time.Time {
+	times := make([]time.Time, n)
+
+	ts := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	for i := range times {
+		ts = ts.Add(time.Duration(rand.Int63n(int64(50 * time.Millisecond))))
+		times[i] = ts
+	}
+
+	return times
+}
+
+func BenchmarkTimestampWriting(b *testing.B) {
+	numRecords := 1000000
+
+	eventTimes := generateEventTimes(numRecords)
+
+	for _, unit := range timestampUnits {
+		unit := unit
+
+		b.Run(unit.name, func(b *testing.B) {
+			prefix := "tswr_" + unit.name + "_"
+
+			data := make([]int64, numRecords)
+			for i, ts := range eventTimes {
+				data[i] = ts.UnixNano() / int64(unit.duration)
+			}
+
+			benchmarkTimestampWriting(b, eventTimes, data, unit, prefix)
+		})
+	}
+}
+
+func benchmarkTimestampWriting(b *testing.B, times []time.Time, data []int64, unit timestampUnit, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(unit.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				type record struct {
+					Ts time.Time `parquet:"ts"`
+				}
+
+				for _, ts := range times {
+					stu := record{
+						Ts: ts,
+					}
+					if err = fw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(unit.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, ts := range data {
+					r := timestampRecord(ts)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(unit.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, ts := range data {
+					stu := map[string]interface{}{
+						"ts": ts,
+					}
+					if err = fw.AddData(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				filename := prefix + "xitongsys_parquet_go.parquet"
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				type record struct {
+					Ts int64
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, unit.xitongsysSchema(), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, ts := range data {
+					stu := record{
+						Ts: ts,
+					}
+					if err = pw.Write(stu); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := unit.arrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						tsCol, ok := col.(*file.Int64ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert ts column which is %T", col)
+						}
+
+						if err := writeBatches(len(data), batchSize, func(start, end int) error {
+							_, err := tsCol.WriteBatch(data[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						tsCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, unit.pqarrowSchema())
+				defer bld.Release()
+
+				tsBld := bld.Field(0).(*array.TimestampBuilder)
+				tsBld.Reserve(len(data))
+				for _, ts := range data {
+					tsBld.UnsafeAppend(arrow.Timestamp(ts))
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			func() {
+				parquetFilename := prefix + "segmentio.parquet"
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, unit.segmentioSchema(), parquet4.Compression(&snappy.Codec{}))
+
+				row := make(parquet4.Row, 1)
+
+				for _, ts := range data {
+					row[0] = parquet4.ValueOf(ts).Level(0, 0, 0)
+					if err := wr.WriteRow(row); err != nil {
+						b.Fatalf("WriteRow failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+	})
+}
+
+type timestampRecord int64
+
+func (r timestampRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("ts").SetInt64(int64(r))
+	return nil
+}
@@ -1,12 +1,19 @@
 package benchmark_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"os"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
 	goparquet "github.com/fraugster/parquet-go"
 	"github.com/fraugster/parquet-go/floor"
 	"github.com/fraugster/parquet-go/floor/interfaces"
@@ -17,6 +24,11 @@ import (
 	"github.com/xitongsys/parquet-go/reader"
 )
 
+// arrowReadBatchSizes are the numbers of values that the arrow column chunk
+// readers request per ReadBatch call, and the number of rows per record that
+// the pqarrow record readers return.
+var arrowReadBatchSizes = []int{1, 64, 1024, 65536}
+
 func BenchmarkInt32Reading(b *testing.B) {
 	numRecords := 1000000
 	data := make([]int32, numRecords)
@@ -208,6 +220,150 @@ func benchmarkInt32Reading(b *testing.B, data []int32, prefix string) {
 			}()
 		}
 	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]int32, batchSize)
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						row := 0
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.Int32ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert foo column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								_, n, err := col.ReadBatch(int64(len(values)), values, nil, nil)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+								for _, v := range values[:n] {
+									if v != data[row] {
+										b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+									}
+									row++
+								}
+							}
+						}
+						if row != len(data) {
+							b.Fatalf("read %d rows, expected %d", row, len(data))
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						for _, v := range rec.Column(0).(*array.Int32).Int32Values() {
+							if v != data[row] {
+								b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+							}
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					for _, v := range chunk.(*array.Int32).Int32Values() {
+						if v != data[row] {
+							b.Fatalf("row %d is %d, expected %d", row, v, data[row])
+						}
+						row++
+					}
+				}
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+// readPqarrowTable reads a whole file through pqarrow into an arrow table.
+// The caller has to release the table.
+func readPqarrowTable(filename string) (arrow.Table, error) {
+	r, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+
+	return fr.ReadTable(context.Background())
+}
+
+// readPqarrowRecords reads a whole file through a pqarrow record reader
+// that returns records of batchSize rows, and calls fn with each record.
+// The record is only valid until fn returns.
+func readPqarrowRecords(filename string, batchSize int, fn func(rec arrow.Record) error) error {
+	r, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}, memory.DefaultAllocator)
+	if err != nil {
+		return err
+	}
+
+	rr, err := fr.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer rr.Release()
+
+	for {
+		rec, err := rr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
 }
 
 type myInt32Record struct {
@@ -222,3 +378,10 @@ func (r *myInt32Record) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
 	r.Foo = i32
 	return nil
 }
+
+// listRange returns the range of list i's elements in a.ListValues(). The
+// offsets of a list aren't adjusted when the list is sliced.
+func listRange(a *array.List, i int) (start, end int) {
+	offsets := a.Offsets()[a.Data().Offset():]
+	return int(offsets[i]), int(offsets[i+1])
+}
@@ -1,13 +1,18 @@
 package benchmark_test
 
 import (
+	"fmt"
 	"math/rand"
 	"os"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
 	parquet3 "github.com/apache/arrow/go/v8/parquet"
 	"github.com/apache/arrow/go/v8/parquet/compress"
 	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
 	"github.com/apache/arrow/go/v8/parquet/schema"
 	goparquet "github.com/fraugster/parquet-go"
 	"github.com/fraugster/parquet-go/floor"
@@ -20,6 +25,10 @@ import (
 	"github.com/xitongsys/parquet-go/writer"
 )
 
+// arrowWriteBatchSizes are the numbers of rows that the apache_arrow_parquet
+// variants pass to each WriteBatch call. 0 writes the whole column at once.
+var arrowWriteBatchSizes = []int{1, 64, 1024, 65536, 0}
+
 func BenchmarkInt32Writing(b *testing.B) {
 	numRecords := 1000000
 
@@ -273,40 +282,69 @@ func benchmarkInt32Writing(b *testing.B, data []int32, prefix string) {
 	})
 
 	b.Run("apache_arrow_parquet", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			func() {
-				filename := prefix + "apache_arrow_parquet.parquet"
-				w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-				if err != nil {
-					b.Fatalf("Opening file failed: %v", err)
-				}
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("foo", parquet3.Repetitions.Required, parquet3.Types.Int32, 0, 0)),
+						}, 0)
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						fooCol, ok := col.(*file.Int32ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert foo column which is %T", col)
+						}
+
+						if err := writeBatches(len(data), batchSize, func(start, end int) error {
+							_, err := fooCol.WriteBatch(data[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						fooCol.Close()
+
+						defer rg.Close()
+					}()
+				}
+			})
+		}
+	})
 
-				sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
-					schema.MustPrimitive(schema.NewPrimitiveNode("foo", parquet3.Repetitions.Required, parquet3.Types.Int32, 0, 0)),
-				}, 0)
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
 
-				pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
-				defer pw.Close()
+		sc := arrow.NewSchema([]arrow.Field{{Name: "foo", Type: arrow.PrimitiveTypes.Int32}}, nil)
 
-				rg := pw.AppendRowGroup()
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
 
-				col, err := rg.NextColumn()
-				if err != nil {
-					b.Fatalf("NextColumn failed: %v", err)
-				}
+				bld.Field(0).(*array.Int32Builder).AppendValues(data, nil)
 
-				fooCol, ok := col.(*file.Int32ColumnChunkWriter)
-				if !ok {
-					b.Fatalf("couldn't assert foo column which is %T", col)
-				}
+				rec := bld.NewRecord()
+				defer rec.Release()
 
-				if _, err := fooCol.WriteBatch(data, nil, nil); err != nil {
-					b.Fatalf("WriteBatch failed: %v", err)
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
 				}
-
-				fooCol.Close()
-
-				defer rg.Close()
 			}()
 		}
 	})
@@ -380,3 +418,91 @@ func (r int32Record) MarshalParquet(obj interfaces.MarshalObject) error {
 	obj.AddField("foo").SetInt32(int32(r))
 	return nil
 }
+
+// writePqarrowRecord writes an arrow record through pqarrow, which
+// derives the parquet schema from the record's schema. Fields that aren't
+// nullable become required columns.
+func writePqarrowRecord(filename string, rec arrow.Record, opts ...pqarrow.WriterOption) error {
+	w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	fw, err := pqarrow.NewFileWriter(rec.Schema(), w,
+		parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy)),
+		pqarrow.NewArrowWriterProperties(opts...),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := fw.Write(rec); err != nil {
+		return err
+	}
+
+	return fw.Close()
+}
+
+// arrowBatchName names the sub-benchmark that passes batchSize rows to each
+// WriteBatch call.
+func arrowBatchName(batchSize int) string {
+	if batchSize == 0 {
+		return "batch_all"
+	}
+	return fmt.Sprintf("batch_%d", batchSize)
+}
+
+// writeBatches calls write for consecutive ranges [start, end) of at most
+// batchSize of n rows. A batchSize of 0 writes all rows at once.
+func writeBatches(n, batchSize int, write func(start, end int) error) error {
+	if batchSize == 0 {
+		batchSize = n
+	}
+
+	for start := 0; start < n; start += batchSize {
+		end := start + batchSize
+		if end > n {
+			end = n
+		}
+		if err := write(start, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeLeveledBatches is writeBatches for columns with definition levels.
+// A row starts at every repetition level 0 (or at every level if there are
+// no repetition levels), and a value is present for every definition level
+// that equals maxDef. write gets the levels and values of at most batchSize
+// rows, so that no row is split across batches.
+func writeLeveledBatches(defLevels, repLevels []int16, maxDef int16, batchSize int, write func(levelStart, levelEnd, valueStart, valueEnd int) error) error {
+	if batchSize == 0 {
+		batchSize = len(defLevels)
+	}
+
+	levelStart, valueStart := 0, 0
+	for levelStart < len(defLevels) {
+		levelEnd, valueEnd, rows := levelStart, valueStart, 0
+		for ; levelEnd < len(defLevels); levelEnd++ {
+			if repLevels == nil || repLevels[levelEnd] == 0 {
+				if rows == batchSize {
+					break
+				}
+				rows++
+			}
+			if defLevels[levelEnd] == maxDef {
+				valueEnd++
+			}
+		}
+
+		if err := write(levelStart, levelEnd, valueStart, valueEnd); err != nil {
+			return err
+		}
+		levelStart, valueStart = levelEnd, valueEnd
+	}
+
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
 	parquet3 "github.com/apache/arrow/go/v8/parquet"
 	"github.com/apache/arrow/go/v8/parquet/compress"
 	"github.com/apache/arrow/go/v8/parquet/file"
@@ -19,6 +22,12 @@ import (
 	"github.com/xitongsys/parquet-go/writer"
 )
 
+const issue84Schema = `message test {
+	required binary format (STRING);
+	required int32 data_type;
+	required binary country (STRING);
+}`
+
 func BenchmarkIssue84(b *testing.B) {
 	numRecords := 1000
 	prefix := "issue84_"
@@ -26,12 +35,7 @@ func BenchmarkIssue84(b *testing.B) {
 	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
 			func() {
-				schemaDef, err := parquetschema.ParseSchemaDefinition(
-					`message test {
-						required binary format (STRING);
-						required int32 data_type;
-						required binary country (STRING);
-					}`)
+				schemaDef, err := parquetschema.ParseSchemaDefinition(issue84Schema)
 				if err != nil {
 					b.Fatalf("Parsing schema definition failed: %v", err)
 				}
@@ -73,12 +77,7 @@ func BenchmarkIssue84(b *testing.B) {
 	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
 			func() {
-				schemaDef, err := parquetschema.ParseSchemaDefinition(
-					`message test {
-						required binary format (STRING);
-						required int32 data_type;
-						required binary country (STRING);
-					}`)
+				schemaDef, err := parquetschema.ParseSchemaDefinition(issue84Schema)
 				if err != nil {
 					b.Fatalf("Parsing schema definition failed: %v", err)
 				}
@@ -114,12 +113,7 @@ func BenchmarkIssue84(b *testing.B) {
 	b.Run("parquet_go_lowlevel", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
 			func() {
-				schemaDef, err := parquetschema.ParseSchemaDefinition(
-					`message test {
-						required binary format (STRING);
-						required int32 data_type;
-						required binary country (STRING);
-					}`)
+				schemaDef, err := parquetschema.ParseSchemaDefinition(issue84Schema)
 				if err != nil {
 					b.Fatalf("Parsing schema definition failed: %v", err)
 				}
@@ -197,79 +191,131 @@ func BenchmarkIssue84(b *testing.B) {
 	})
 
 	b.Run("apache_arrow_parquet", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			func() {
-				filename := prefix + "apache_arrow_parquet.parquet"
-				w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-				if err != nil {
-					b.Fatalf("Opening file failed: %v", err)
-				}
-
-				sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
-					schema.MustPrimitive(schema.NewPrimitiveNodeLogical("format", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
-					schema.MustPrimitive(schema.NewPrimitiveNode("data_type", parquet3.Repetitions.Required, parquet3.Types.Int32, 0, 0)),
-					schema.MustPrimitive(schema.NewPrimitiveNodeLogical("country", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
-				}, 0)
-
-				pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
-				defer pw.Close()
-
-				rg := pw.AppendRowGroup()
-
-				col, err := rg.NextColumn()
-				if err != nil {
-					b.Fatalf("NextColumn failed: %v", err)
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNodeLogical("format", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+							schema.MustPrimitive(schema.NewPrimitiveNode("data_type", parquet3.Repetitions.Required, parquet3.Types.Int32, 0, 0)),
+							schema.MustPrimitive(schema.NewPrimitiveNodeLogical("country", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+						}, 0)
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						var (
+							formats   = make([]parquet3.ByteArray, numRecords)
+							dataTypes = make([]int32, numRecords)
+							countries = make([]parquet3.ByteArray, numRecords)
+						)
+						for i := 0; i < numRecords; i++ {
+							formats[i] = []byte("Test")
+							dataTypes[i] = 1
+							countries[i] = []byte("IN")
+						}
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						formatCol, ok := col.(*file.ByteArrayColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert first column which is %T", col)
+						}
+
+						if err := writeBatches(numRecords, batchSize, func(start, end int) error {
+							_, err := formatCol.WriteBatch(formats[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+						formatCol.Close()
+
+						col, err = rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						dataTypeCol, ok := col.(*file.Int32ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert second column which is %T", col)
+						}
+
+						if err := writeBatches(numRecords, batchSize, func(start, end int) error {
+							_, err := dataTypeCol.WriteBatch(dataTypes[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						dataTypeCol.Close()
+
+						col, err = rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						countryCol, ok := col.(*file.ByteArrayColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert third column which is %T", col)
+						}
+
+						if err := writeBatches(numRecords, batchSize, func(start, end int) error {
+							_, err := countryCol.WriteBatch(countries[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						countryCol.Close()
+
+						defer rg.Close()
+					}()
 				}
+			})
+		}
+	})
 
-				formatCol, ok := col.(*file.ByteArrayColumnChunkWriter)
-				if !ok {
-					b.Fatalf("couldn't assert first column which is %T", col)
-				}
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
 
-				for i := 0; i < numRecords; i++ {
-					if _, err := formatCol.WriteBatch([]parquet3.ByteArray{[]byte("Test")}, nil, nil); err != nil {
-						b.Fatalf("WriteBatch failed: %v", err)
-					}
-				}
-				formatCol.Close()
+		sc := arrow.NewSchema([]arrow.Field{
+			{Name: "format", Type: arrow.BinaryTypes.String},
+			{Name: "data_type", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "country", Type: arrow.BinaryTypes.String},
+		}, nil)
 
-				col, err = rg.NextColumn()
-				if err != nil {
-					b.Fatalf("NextColumn failed: %v", err)
-				}
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
 
-				dataTypeCol, ok := col.(*file.Int32ColumnChunkWriter)
-				if !ok {
-					b.Fatalf("couldn't assert second column which is %T", col)
-				}
+				formatBld := bld.Field(0).(*array.StringBuilder)
+				dataTypeBld := bld.Field(1).(*array.Int32Builder)
+				countryBld := bld.Field(2).(*array.StringBuilder)
 
 				for i := 0; i < numRecords; i++ {
-					if _, err := dataTypeCol.WriteBatch([]int32{1}, nil, nil); err != nil {
-						b.Fatalf("WriteBatch failed: %v", err)
-					}
+					formatBld.Append("Test")
+					dataTypeBld.Append(1)
+					countryBld.Append("IN")
 				}
 
-				dataTypeCol.Close()
+				rec := bld.NewRecord()
+				defer rec.Release()
 
-				col, err = rg.NextColumn()
-				if err != nil {
-					b.Fatalf("NextColumn failed: %v", err)
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
 				}
-
-				countryCol, ok := col.(*file.ByteArrayColumnChunkWriter)
-				if !ok {
-					b.Fatalf("couldn't assert third column which is %T", col)
-				}
-
-				for i := 0; i < numRecords; i++ {
-					if _, err := countryCol.WriteBatch([]parquet3.ByteArray{[]byte("IN")}, nil, nil); err != nil {
-						b.Fatalf("WriteBatch failed: %v", err)
-					}
-				}
-
-				countryCol.Close()
-
-				defer rg.Close()
 			}()
 		}
 	})
@@ -0,0 +1,407 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// The values BenchmarkIssue84 writes into every row.
+const (
+	issue84Format   = "Test"
+	issue84DataType = 1
+	issue84Country  = "IN"
+)
+
+func BenchmarkIssue84Reading(b *testing.B) {
+	b.Run("1k", func(b *testing.B) {
+		benchmarkIssue84Reading(b, 1000, "issue84rd_1k_")
+	})
+
+	b.Run("1m", func(b *testing.B) {
+		benchmarkIssue84Reading(b, 1000000, "issue84rd_1m_")
+	})
+}
+
+// benchmarkIssue84Reading reads the format/data_type/country schema of
+// BenchmarkIssue84. With only 1000 rows the per-column setup costs
+// dominate, the 1m variant shows the per-row decoding costs.
+func benchmarkIssue84Reading(b *testing.B, numRecords int, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(issue84Schema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for i := 0; i < numRecords; i++ {
+		stu := &marshalRecord{
+			Format:   issue84Format,
+			DataType: issue84DataType,
+			Country:  issue84Country,
+		}
+		if err = fw.Write(stu); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	check := func(b *testing.B, row int, format string, dataType int32, country string) {
+		if row >= numRecords {
+			b.Fatalf("read more than the %d rows that were written", numRecords)
+		}
+		if format != issue84Format || dataType != issue84DataType || country != issue84Country {
+			b.Fatalf("row %d is %q/%d/%q, expected %q/%d/%q", row, format, dataType, country, issue84Format, issue84DataType, issue84Country)
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for row := 0; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					format, _ := values["format"].([]byte)
+					dataType, _ := values["data_type"].(int32)
+					country, _ := values["country"].([]byte)
+					check(b, row, string(format), dataType, string(country))
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type record struct {
+			Format   string `parquet:"format"`
+			DataType int32  `parquet:"data_type"`
+			Country  string `parquet:"country"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var rec record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, rec.Format, rec.DataType, rec.Country)
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var rec myIssue84Record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, rec.Format, rec.DataType, rec.Country)
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Format   string `parquet:"name=format, type=BYTE_ARRAY, convertedtype=UTF8"`
+			DataType int32  `parquet:"name=data_type, type=INT32"`
+			Country  string `parquet:"name=country, type=BYTE_ARRAY, convertedtype=UTF8"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					for _, r := range rec {
+						check(b, row, r.Format, r.DataType, r.Country)
+						row++
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		type record struct {
+			Format   string `parquet:"format"`
+			DataType int32  `parquet:"data_type"`
+			Country  string `parquet:"country"`
+		}
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				for row := 0; ; row++ {
+					var rec record
+					if err := r.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					check(b, row, rec.Format, rec.DataType, rec.Country)
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				byteArrayValues := make([]parquet3.ByteArray, batchSize)
+				int32Values := make([]int32, batchSize)
+
+				// arrow reads column by column, so each column is checked on
+				// its own and only the row counts need to line up.
+				checkStrings := func(b *testing.B, col file.ColumnChunkReader, expected string) int {
+					c, ok := col.(*file.ByteArrayColumnChunkReader)
+					if !ok {
+						b.Fatalf("couldn't assert %s column which is %T", col.Descriptor().Name(), col)
+					}
+
+					rows := 0
+					for c.HasNext() {
+						_, n, err := c.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						for _, v := range byteArrayValues[:n] {
+							if string(v) != expected {
+								b.Fatalf("%s in row %d is %q, expected %q", col.Descriptor().Name(), rows, v, expected)
+							}
+							rows++
+						}
+					}
+					return rows
+				}
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						rows := 0
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							rgr := r.RowGroup(rg)
+
+							formatRows := checkStrings(b, rgr.Column(0), issue84Format)
+
+							dataTypeCol, ok := rgr.Column(1).(*file.Int32ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert data_type column which is %T", rgr.Column(1))
+							}
+
+							dataTypeRows := 0
+							for dataTypeCol.HasNext() {
+								_, n, err := dataTypeCol.ReadBatch(int64(len(int32Values)), int32Values, nil, nil)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+								for _, v := range int32Values[:n] {
+									if v != issue84DataType {
+										b.Fatalf("data_type in row %d is %d, expected %d", dataTypeRows, v, issue84DataType)
+									}
+									dataTypeRows++
+								}
+							}
+
+							countryRows := checkStrings(b, rgr.Column(2), issue84Country)
+
+							if formatRows != dataTypeRows || formatRows != countryRows {
+								b.Fatalf("columns have %d/%d/%d rows", formatRows, dataTypeRows, countryRows)
+							}
+							rows += formatRows
+						}
+
+						if rows != numRecords {
+							b.Fatalf("read %d rows, expected %d", rows, numRecords)
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						formats := rec.Column(0).(*array.String)
+						dataTypes := rec.Column(1).(*array.Int32)
+						countries := rec.Column(2).(*array.String)
+						for j := 0; j < int(rec.NumRows()); j++ {
+							check(b, row, formats.Value(j), dataTypes.Value(j), countries.Value(j))
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != numRecords {
+						b.Fatalf("read %d rows, expected %d", row, numRecords)
+					}
+				}
+			})
+		}
+	})
+
+	// the columns of a table may be chunked differently, so the table is
+	// read as records whose columns line up.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				tr := array.NewTableReader(tbl, -1)
+				defer tr.Release()
+
+				row := 0
+				for tr.Next() {
+					rec := tr.Record()
+					formats := rec.Column(0).(*array.String)
+					dataTypes := rec.Column(1).(*array.Int32)
+					countries := rec.Column(2).(*array.String)
+					for j := 0; j < int(rec.NumRows()); j++ {
+						check(b, row, formats.Value(j), dataTypes.Value(j), countries.Value(j))
+						row++
+					}
+				}
+				if row != numRecords {
+					b.Fatalf("read %d rows, expected %d", row, numRecords)
+				}
+			}()
+		}
+	})
+}
+
+type myIssue84Record struct {
+	Format   string
+	DataType int32
+	Country  string
+}
+
+func (r *myIssue84Record) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	format, err := obj.GetField("format").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.Format = string(format)
+
+	dataType, err := obj.GetField("data_type").Int32()
+	if err != nil {
+		return err
+	}
+	r.DataType = dataType
+
+	country, err := obj.GetField("country").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.Country = string(country)
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkMapReading(b *testing.B) {
+	for _, m := range mapTypes {
+		m := m
+
+		b.Run(m.name, func(b *testing.B) {
+			for _, size := range mapSizes {
+				size := size
+
+				b.Run(size.name, func(b *testing.B) {
+					data := generateMaps(m, size)
+					b.ResetTimer()
+
+					benchmarkMapReading(b, data, m, "maprd_"+m.name+"_"+size.name+"_")
+				})
+			}
+		})
+	}
+}
+
+func benchmarkMapReading(b *testing.B, data mapData, m mapType, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(m.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for i := 0; i < data.len(); i++ {
+		if err = fw.Write(mapRecord{data: data, idx: i}); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	check := func(b *testing.B, row int, v interface{}) {
+		if row >= data.len() {
+			b.Fatalf("read more than the %d rows that were written", data.len())
+		}
+		if err := data.compare(row, v); err != nil {
+			b.Fatalf("row %d: %v", row, err)
+		}
+	}
+
+	// readMaps runs a mapReader and checks all maps it returns.
+	readMaps := func(b *testing.B, read mapReader) {
+		numRows := 0
+		err := read(parquetFilename, m, func(row int, v interface{}) error {
+			check(b, row, v)
+			numRows++
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("Reading maps failed: %v", err)
+		}
+		if numRows != data.len() {
+			b.Fatalf("read %d rows, expected %d", numRows, data.len())
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			readMaps(b, readParquetGoMaps)
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		b.Skip("floor's reflection unmarshaller fails on empty maps")
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					rec := myMapRecord{int64Values: m.int64Values}
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					if m.int64Values {
+						check(b, row, rec.Int64s)
+					} else {
+						check(b, row, rec.Strings)
+					}
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				var pr *reader.ParquetReader
+				if m.int64Values {
+					pr, err = reader.NewParquetReader(fr, new(xitongsysInt64MapRecord), 1)
+				} else {
+					pr, err = reader.NewParquetReader(fr, new(xitongsysStringMapRecord), 1)
+				}
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+
+					if m.int64Values {
+						rec := make([]xitongsysInt64MapRecord, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								check(b, row, r.Attributes)
+								row++
+							}
+						}
+					} else {
+						rec := make([]xitongsysStringMapRecord, sliceSize)
+						if err = pr.Read(&rec); err == nil {
+							for _, r := range rec {
+								check(b, row, r.Attributes)
+								row++
+							}
+						}
+					}
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			readMaps(b, readSegmentioMaps)
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				read := arrowMapReader(batchSize)
+				for i := 0; i < b.N; i++ {
+					readMaps(b, read)
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				read := pqarrowRecordMapReader(batchSize)
+				for i := 0; i < b.N; i++ {
+					readMaps(b, read)
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			readMaps(b, readPqarrowMaps)
+		}
+	})
+}
+
+type myMapRecord struct {
+	int64Values bool
+	Strings     map[string]string
+	Int64s      map[string]int64
+}
+
+func (r *myMapRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	attributes, err := obj.GetField("attributes").Group()
+	if err != nil {
+		return err
+	}
+
+	if r.int64Values {
+		r.Int64s = map[string]int64{}
+	} else {
+		r.Strings = map[string]string{}
+	}
+
+	// an empty map comes without the repeated group.
+	if attributes.GetField("key_value").Error() != nil {
+		return nil
+	}
+
+	kvs, err := obj.GetField("attributes").Map()
+	if err != nil {
+		return err
+	}
+
+	for kvs.Next() {
+		key, err := kvs.Key()
+		if err != nil {
+			return err
+		}
+		k, err := key.ByteArray()
+		if err != nil {
+			return err
+		}
+
+		value, err := kvs.Value()
+		if err != nil {
+			return err
+		}
+
+		if r.int64Values {
+			v, err := value.Int64()
+			if err != nil {
+				return err
+			}
+			r.Int64s[string(k)] = v
+		} else {
+			v, err := value.ByteArray()
+			if err != nil {
+				return err
+			}
+			r.Strings[string(k)] = string(v)
+		}
+	}
+
+	return nil
+}
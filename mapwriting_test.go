@@ -0,0 +1,985 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// mapType describes a MAP column with string keys and either string or
+// int64 values.
+type mapType struct {
+	name        string
+	int64Values bool
+}
+
+var mapTypes = []mapType{
+	{name: "string_string", int64Values: false},
+	{name: "string_int64", int64Values: true},
+}
+
+// mapSize describes how many entries each map has at most. Smaller maps
+// come with more records so that all variants write a similar number of
+// entries.
+type mapSize struct {
+	name       string
+	maxEntries int
+	numRecords int
+}
+
+var mapSizes = []mapSize{
+	{name: "small", maxEntries: 4, numRecords: 100000},
+	{name: "medium", maxEntries: 32, numRecords: 20000},
+	{name: "large", maxEntries: 256, numRecords: 2500},
+}
+
+func (m mapType) schemaDefinition() string {
+	value := "binary value (STRING)"
+	if m.int64Values {
+		value = "int64 value"
+	}
+	return fmt.Sprintf(`message test {
+	required group attributes (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			required %s;
+		}
+	}
+}`, value)
+}
+
+func (m mapType) arrowSchema() (*schema.GroupNode, error) {
+	value := schema.MustPrimitive(schema.NewPrimitiveNodeLogical("value", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0))
+	if m.int64Values {
+		value = schema.MustPrimitive(schema.NewPrimitiveNode("value", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0))
+	}
+
+	keyValue, err := schema.NewGroupNode("key_value", parquet3.Repetitions.Repeated, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("key", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+		value,
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := schema.NewGroupNodeLogical("attributes", parquet3.Repetitions.Required, schema.FieldList{keyValue}, schema.MapLogicalType{}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{attributes}, 0)
+}
+
+// pqarrowSchema returns the arrow schema of the map column. arrow's map
+// builder always produces nullable values, so unlike in schemaDefinition the
+// value column is optional.
+func (m mapType) pqarrowSchema() *arrow.Schema {
+	mt := arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)
+	if m.int64Values {
+		mt = arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64)
+	}
+
+	return arrow.NewSchema([]arrow.Field{{Name: "attributes", Type: mt}}, nil)
+}
+
+// mapData holds the generated maps, in strings or int64s depending on the
+// mapType they were generated for.
+type mapData struct {
+	strings []map[string]string
+	int64s  []map[string]int64
+}
+
+func (d mapData) len() int {
+	if d.int64s != nil {
+		return len(d.int64s)
+	}
+	return len(d.strings)
+}
+
+// entries returns the number of entries of map i.
+func (d mapData) entries(i int) int {
+	if d.int64s != nil {
+		return len(d.int64s[i])
+	}
+	return len(d.strings[i])
+}
+
+// generateMaps returns size.numRecords maps of up to size.maxEntries
+// entries each, including empty ones. The keys are drawn from a pool of
+// twice the maximum map size.
+func generateMaps(m mapType, size mapSize) mapData {
+	var d mapData
+	if m.int64Values {
+		d.int64s = make([]map[string]int64, size.numRecords)
+	} else {
+		d.strings = make([]map[string]string, size.numRecords)
+	}
+
+	for i := 0; i < size.numRecords; i++ {
+		keys := rand.Perm(2 * size.maxEntries)[:rand.Intn(size.maxEntries+1)]
+		if m.int64Values {
+			d.int64s[i] = make(map[string]int64, len(keys))
+			for _, k := range keys {
+				d.int64s[i][fmt.Sprintf("attr_%d", k)] = rand.Int63()
+			}
+		} else {
+			d.strings[i] = make(map[string]string, len(keys))
+			for _, k := range keys {
+				d.strings[i][fmt.Sprintf("attr_%d", k)] = fmt.Sprintf("value_%d", rand.Intn(1000))
+			}
+		}
+	}
+
+	return d
+}
+
+// object returns map i in the representation used by parquet-go's low-level
+// API. Like with lists, an empty map has to leave out the repeated group.
+func (d mapData) object(i int) map[string]interface{} {
+	attributes := map[string]interface{}{}
+
+	var keyValues []map[string]interface{}
+	if d.int64s != nil {
+		for k, v := range d.int64s[i] {
+			keyValues = append(keyValues, map[string]interface{}{"key": []byte(k), "value": v})
+		}
+	} else {
+		for k, v := range d.strings[i] {
+			keyValues = append(keyValues, map[string]interface{}{"key": []byte(k), "value": []byte(v)})
+		}
+	}
+	if len(keyValues) > 0 {
+		attributes["key_value"] = keyValues
+	}
+
+	return map[string]interface{}{
+		"attributes": attributes,
+	}
+}
+
+// compare returns an error describing the first difference between a
+// decoded map and map i, which must be of the same type. A nil map equals
+// an empty one.
+func (d mapData) compare(i int, got interface{}) error {
+	switch got := got.(type) {
+	case map[string]string:
+		want := d.strings[i]
+		if len(got) != len(want) {
+			return fmt.Errorf("got %d entries, expected %d", len(got), len(want))
+		}
+		for k, v := range want {
+			if gv, ok := got[k]; !ok || gv != v {
+				return fmt.Errorf("%s is %q, expected %q", k, gv, v)
+			}
+		}
+	case map[string]int64:
+		want := d.int64s[i]
+		if len(got) != len(want) {
+			return fmt.Errorf("got %d entries, expected %d", len(got), len(want))
+		}
+		for k, v := range want {
+			if gv, ok := got[k]; !ok || gv != v {
+				return fmt.Errorf("%s is %d, expected %d", k, gv, v)
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected map %T", got)
+	}
+	return nil
+}
+
+// mapFromRow is the inverse of mapData.object. It returns a map[string]string
+// or a map[string]int64 depending on the type of the values.
+func mapFromRow(row map[string]interface{}, m mapType) (interface{}, error) {
+	attributes, ok := row["attributes"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected attributes %T", row["attributes"])
+	}
+
+	keyValues, _ := attributes["key_value"].([]map[string]interface{})
+
+	strs := make(map[string]string, len(keyValues))
+	int64s := make(map[string]int64, len(keyValues))
+
+	for _, kv := range keyValues {
+		k, ok := kv["key"].([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected key %T", kv["key"])
+		}
+		switch v := kv["value"].(type) {
+		case []byte:
+			strs[string(k)] = string(v)
+		case int64:
+			int64s[string(k)] = v
+		default:
+			return nil, fmt.Errorf("unexpected value %T", v)
+		}
+	}
+
+	if m.int64Values {
+		return int64s, nil
+	}
+	return strs, nil
+}
+
+// mapReader reads all maps from a file and calls fn with each of them in
+// order. The maps are of the same type as the ones returned by mapFromRow.
+type mapReader func(filename string, m mapType, fn func(row int, v interface{}) error) error
+
+// arrowMapReader returns a mapReader that uses apache arrow's column
+// readers, which read batchSize levels at a time.
+func arrowMapReader(batchSize int) mapReader {
+	return func(filename string, m mapType, fn func(row int, v interface{}) error) error {
+		return readArrowMaps(filename, m, batchSize, fn)
+	}
+}
+
+// readArrowMaps reads the maps of filename with apache arrow's column readers
+// and calls fn with each of them.
+func readArrowMaps(filename string, m mapType, batchSize int, fn func(row int, v interface{}) error) error {
+	r, err := file.OpenParquetFile(filename, false)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var (
+		byteArrayValues = make([]parquet3.ByteArray, batchSize)
+		int64Values     = make([]int64, batchSize)
+		defLevels       = make([]int16, batchSize)
+		repLevels       = make([]int16, batchSize)
+	)
+
+	row := 0
+
+	for rg := 0; rg < r.NumRowGroups(); rg++ {
+		rgr := r.RowGroup(rg)
+
+		// keys and values are read one column after the other, so the
+		// keys of the whole row group are kept together with the number
+		// of entries of each map until the values are read.
+		var (
+			keys    []string
+			entries []int
+		)
+
+		keyCol, ok := rgr.Column(0).(*file.ByteArrayColumnChunkReader)
+		if !ok {
+			return fmt.Errorf("unexpected key column %T", rgr.Column(0))
+		}
+
+		for keyCol.HasNext() {
+			numLevels, _, err := keyCol.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, defLevels, repLevels)
+			if err != nil {
+				return err
+			}
+			valueIdx := 0
+			for idx := 0; idx < int(numLevels); idx++ {
+				if repLevels[idx] == 0 {
+					entries = append(entries, 0)
+				}
+				if defLevels[idx] == 1 {
+					keys = append(keys, string(byteArrayValues[valueIdx]))
+					entries[len(entries)-1]++
+					valueIdx++
+				}
+			}
+		}
+
+		var (
+			strs   []string
+			int64s []int64
+		)
+
+		switch valueCol := rgr.Column(1).(type) {
+		case *file.ByteArrayColumnChunkReader:
+			for valueCol.HasNext() {
+				_, n, err := valueCol.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, defLevels, repLevels)
+				if err != nil {
+					return err
+				}
+				for _, v := range byteArrayValues[:n] {
+					strs = append(strs, string(v))
+				}
+			}
+		case *file.Int64ColumnChunkReader:
+			for valueCol.HasNext() {
+				_, n, err := valueCol.ReadBatch(int64(len(int64Values)), int64Values, defLevels, repLevels)
+				if err != nil {
+					return err
+				}
+				int64s = append(int64s, int64Values[:n]...)
+			}
+		default:
+			return fmt.Errorf("unexpected value column %T", valueCol)
+		}
+
+		if len(strs)+len(int64s) != len(keys) {
+			return fmt.Errorf("row group %d has %d keys but %d values", rg, len(keys), len(strs)+len(int64s))
+		}
+
+		kvIdx := 0
+		for _, n := range entries {
+			var v interface{}
+			if m.int64Values {
+				mv := make(map[string]int64, n)
+				for _, k := range keys[kvIdx : kvIdx+n] {
+					mv[k] = int64s[kvIdx]
+					kvIdx++
+				}
+				v = mv
+			} else {
+				mv := make(map[string]string, n)
+				for _, k := range keys[kvIdx : kvIdx+n] {
+					mv[k] = strs[kvIdx]
+					kvIdx++
+				}
+				v = mv
+			}
+			if err := fn(row, v); err != nil {
+				return err
+			}
+			row++
+		}
+	}
+
+	return nil
+}
+
+// readPqarrowMaps is a mapReader that reads the whole file into an arrow
+// table through pqarrow.
+func readPqarrowMaps(filename string, m mapType, fn func(row int, v interface{}) error) error {
+	tbl, err := readPqarrowTable(filename)
+	if err != nil {
+		return err
+	}
+	defer tbl.Release()
+
+	row := 0
+
+	for _, chunk := range tbl.Column(0).Data().Chunks() {
+		if row, err = pqarrowMaps(chunk, row, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pqarrowRecordMapReader returns a mapReader that reads the file through a
+// pqarrow record reader, which returns records of batchSize rows.
+func pqarrowRecordMapReader(batchSize int) mapReader {
+	return func(filename string, m mapType, fn func(row int, v interface{}) error) error {
+		row := 0
+		return readPqarrowRecords(filename, batchSize, func(rec arrow.Record) error {
+			var err error
+			row, err = pqarrowMaps(rec.Column(0), row, fn)
+			return err
+		})
+	}
+}
+
+// pqarrowMaps calls fn with each map of arr, numbering them from row on,
+// and returns the row number that follows the last map.
+func pqarrowMaps(arr arrow.Array, row int, fn func(row int, v interface{}) error) (int, error) {
+	maps, ok := arr.(*array.Map)
+	if !ok {
+		return row, fmt.Errorf("unexpected attributes array %T", arr)
+	}
+
+	keys, ok := maps.Keys().(*array.String)
+	if !ok {
+		return row, fmt.Errorf("unexpected key array %T", maps.Keys())
+	}
+
+	for j := 0; j < maps.Len(); j++ {
+		start, end := listRange(maps.List, j)
+
+		var v interface{}
+		switch items := maps.Items().(type) {
+		case *array.String:
+			mv := make(map[string]string, end-start)
+			for idx := start; idx < end; idx++ {
+				mv[keys.Value(idx)] = items.Value(idx)
+			}
+			v = mv
+		case *array.Int64:
+			mv := make(map[string]int64, end-start)
+			for idx := start; idx < end; idx++ {
+				mv[keys.Value(idx)] = items.Value(idx)
+			}
+			v = mv
+		default:
+			return row, fmt.Errorf("unexpected value array %T", items)
+		}
+
+		if err := fn(row, v); err != nil {
+			return row, err
+		}
+		row++
+	}
+
+	return row, nil
+}
+
+// readParquetGoMaps is a mapReader that uses parquet-go's low-level reader.
+func readParquetGoMaps(filename string, m mapType, fn func(row int, v interface{}) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		return err
+	}
+
+	for row := 0; ; row++ {
+		values, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		v, err := mapFromRow(values, m)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		if err := fn(row, v); err != nil {
+			return err
+		}
+	}
+}
+
+// readSegmentioMaps is a mapReader that uses segmentio's reader.
+func readSegmentioMaps(filename string, m mapType, fn func(row int, v interface{}) error) error {
+	type stringRecord struct {
+		Attributes map[string]string `parquet:"attributes"`
+	}
+
+	type int64Record struct {
+		Attributes map[string]int64 `parquet:"attributes"`
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := parquet4.NewReader(f)
+
+	for row := 0; ; row++ {
+		var v interface{}
+		if m.int64Values {
+			var rec int64Record
+			err = r.Read(&rec)
+			v = rec.Attributes
+		} else {
+			var rec stringRecord
+			err = r.Read(&rec)
+			v = rec.Attributes
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := fn(row, v); err != nil {
+			return err
+		}
+	}
+}
+
+// verifyMapFile reads back a file using read and checks that every map,
+// including the empty ones, survived the round trip unchanged. None of the
+// readers can decode all files: parquet-go loses entries from the maps
+// that xitongsys writes and sometimes fails on segmentio's pages, and
+// arrow can't decode the levels in segmentio's data pages.
+func verifyMapFile(b *testing.B, filename string, m mapType, data mapData, read mapReader) {
+	numRows := 0
+
+	err := read(filename, m, func(row int, v interface{}) error {
+		if row >= data.len() {
+			return fmt.Errorf("read more than the %d rows that were written", data.len())
+		}
+		if err := data.compare(row, v); err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+		numRows++
+		return nil
+	})
+	if err != nil {
+		b.Fatalf("%s: %v", filename, err)
+	}
+
+	if numRows != data.len() {
+		b.Fatalf("%s: read %d rows, expected %d", filename, numRows, data.len())
+	}
+}
+
+func BenchmarkMapWriting(b *testing.B) {
+	for _, m := range mapTypes {
+		m := m
+
+		b.Run(m.name, func(b *testing.B) {
+			for _, size := range mapSizes {
+				size := size
+
+				b.Run(size.name, func(b *testing.B) {
+					prefix := "mapwr_" + m.name + "_" + size.name + "_"
+
+					data := generateMaps(m, size)
+
+					benchmarkMapWriting(b, data, m, prefix)
+				})
+			}
+		})
+	}
+}
+
+func benchmarkMapWriting(b *testing.B, data mapData, m mapType, prefix string) {
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+		type stringRecord struct {
+			Attributes map[string]string `parquet:"attributes"`
+		}
+
+		type int64Record struct {
+			Attributes map[string]int64 `parquet:"attributes"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(m.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				// floor turns empty maps into a key_value group without
+				// any entries, which the writer then rejects. A nil map
+				// leaves the group out instead.
+				for i := 0; i < data.len(); i++ {
+					if m.int64Values {
+						attributes := data.int64s[i]
+						if len(attributes) == 0 {
+							attributes = nil
+						}
+						err = fw.Write(int64Record{Attributes: attributes})
+					} else {
+						attributes := data.strings[i]
+						if len(attributes) == 0 {
+							attributes = nil
+						}
+						err = fw.Write(stringRecord{Attributes: attributes})
+					}
+					if err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyMapFile(b, parquetFilename, m, data, readParquetGoMaps)
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(m.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for i := 0; i < data.len(); i++ {
+					if err = fw.Write(mapRecord{data: data, idx: i}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyMapFile(b, parquetFilename, m, data, readParquetGoMaps)
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(m.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for i := 0; i < data.len(); i++ {
+					if err = fw.AddData(data.object(i)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyMapFile(b, parquetFilename, m, data, readParquetGoMaps)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				//write
+				var pw *writer.ParquetWriter
+				if m.int64Values {
+					pw, err = writer.NewParquetWriterFromWriter(w, new(xitongsysInt64MapRecord), 4)
+				} else {
+					pw, err = writer.NewParquetWriterFromWriter(w, new(xitongsysStringMapRecord), 4)
+				}
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for i := 0; i < data.len(); i++ {
+					if m.int64Values {
+						err = pw.Write(xitongsysInt64MapRecord{Attributes: data.int64s[i]})
+					} else {
+						err = pw.Write(xitongsysStringMapRecord{Attributes: data.strings[i]})
+					}
+					if err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+
+		b.StopTimer()
+		verifyMapFile(b, filename, m, data, arrowMapReader(1024))
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := m.arrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						// keys and values share their levels: an empty map is a
+						// single undefined entry, and every entry but the first
+						// one of a map repeats the key_value group.
+						var (
+							keys       []parquet3.ByteArray
+							stringVals []parquet3.ByteArray
+							int64Vals  []int64
+							defLevels  []int16
+							repLevels  []int16
+						)
+
+						addEntry := func(j int, k string) {
+							keys = append(keys, parquet3.ByteArray(k))
+							defLevels = append(defLevels, 1)
+							if j == 0 {
+								repLevels = append(repLevels, 0)
+							} else {
+								repLevels = append(repLevels, 1)
+							}
+						}
+
+						for i := 0; i < data.len(); i++ {
+							j := 0
+							if m.int64Values {
+								for k, v := range data.int64s[i] {
+									addEntry(j, k)
+									int64Vals = append(int64Vals, v)
+									j++
+								}
+							} else {
+								for k, v := range data.strings[i] {
+									addEntry(j, k)
+									stringVals = append(stringVals, parquet3.ByteArray(v))
+									j++
+								}
+							}
+							if j == 0 {
+								defLevels = append(defLevels, 0)
+								repLevels = append(repLevels, 0)
+							}
+						}
+
+						rg := pw.AppendRowGroup()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						keyCol, ok := col.(*file.ByteArrayColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert key column which is %T", col)
+						}
+
+						if err := writeLeveledBatches(defLevels, repLevels, 1, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+							_, err := keyCol.WriteBatch(keys[valueStart:valueEnd], defLevels[levelStart:levelEnd], repLevels[levelStart:levelEnd])
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						keyCol.Close()
+
+						col, err = rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						switch valueCol := col.(type) {
+						case *file.ByteArrayColumnChunkWriter:
+							err = writeLeveledBatches(defLevels, repLevels, 1, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+								_, err := valueCol.WriteBatch(stringVals[valueStart:valueEnd], defLevels[levelStart:levelEnd], repLevels[levelStart:levelEnd])
+								return err
+							})
+						case *file.Int64ColumnChunkWriter:
+							err = writeLeveledBatches(defLevels, repLevels, 1, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+								_, err := valueCol.WriteBatch(int64Vals[valueStart:valueEnd], defLevels[levelStart:levelEnd], repLevels[levelStart:levelEnd])
+								return err
+							})
+						default:
+							b.Fatalf("unexpected value column %T", col)
+						}
+						if err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						col.Close()
+
+						defer rg.Close()
+					}()
+				}
+
+				b.StopTimer()
+				verifyMapFile(b, filename, m, data, readParquetGoMaps)
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := m.pqarrowSchema()
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				mapBld := bld.Field(0).(*array.MapBuilder)
+				keyBld := mapBld.KeyBuilder().(*array.StringBuilder)
+
+				for i := 0; i < data.len(); i++ {
+					mapBld.Append(true)
+					if m.int64Values {
+						valueBld := mapBld.ItemBuilder().(*array.Int64Builder)
+						for k, v := range data.int64s[i] {
+							keyBld.Append(k)
+							valueBld.Append(v)
+						}
+					} else {
+						valueBld := mapBld.ItemBuilder().(*array.StringBuilder)
+						for k, v := range data.strings[i] {
+							keyBld.Append(k)
+							valueBld.Append(v)
+						}
+					}
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyMapFile(b, filename, m, data, arrowMapReader(1024))
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio.parquet"
+
+		type stringRecord struct {
+			Attributes map[string]string `parquet:"attributes"`
+		}
+
+		type int64Record struct {
+			Attributes map[string]int64 `parquet:"attributes"`
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				var wr *parquet4.Writer
+				if m.int64Values {
+					wr = parquet4.NewWriter(f, parquet4.SchemaOf(new(int64Record)), parquet4.Compression(&snappy.Codec{}))
+				} else {
+					wr = parquet4.NewWriter(f, parquet4.SchemaOf(new(stringRecord)), parquet4.Compression(&snappy.Codec{}))
+				}
+
+				for i := 0; i < data.len(); i++ {
+					if m.int64Values {
+						err = wr.Write(&int64Record{Attributes: data.int64s[i]})
+					} else {
+						err = wr.Write(&stringRecord{Attributes: data.strings[i]})
+					}
+					if err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyMapFile(b, parquetFilename, m, data, readSegmentioMaps)
+	})
+}
+
+type xitongsysStringMapRecord struct {
+	Attributes map[string]string `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+}
+
+type xitongsysInt64MapRecord struct {
+	Attributes map[string]int64 `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=INT64"`
+}
+
+type mapRecord struct {
+	data mapData
+	idx  int
+}
+
+func (r mapRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	// Map always adds the key_value group, which an empty map has to
+	// leave out.
+	if r.data.entries(r.idx) == 0 {
+		obj.AddField("attributes").Group()
+		return nil
+	}
+
+	attributes := obj.AddField("attributes").Map()
+
+	if r.data.int64s != nil {
+		for k, v := range r.data.int64s[r.idx] {
+			kv := attributes.Add()
+			kv.Key().SetByteArray([]byte(k))
+			kv.Value().SetInt64(v)
+		}
+	} else {
+		for k, v := range r.data.strings[r.idx] {
+			kv := attributes.Add()
+			kv.Key().SetByteArray([]byte(k))
+			kv.Value().SetByteArray([]byte(v))
+		}
+	}
+
+	return nil
+}
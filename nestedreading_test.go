@@ -0,0 +1,466 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkNestedReading(b *testing.B) {
+	data := generateNestedEvents(100000)
+
+	schemaDef, err := parquetschema.ParseSchemaDefinition(nestedSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := "nestedrd_testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for _, ev := range data {
+		r := nestedRecord(ev)
+		if err = fw.Write(&r); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	check := func(b *testing.B, row int, ev nestedEvent) {
+		if row >= len(data) {
+			b.Fatalf("read more than the %d rows that were written", len(data))
+		}
+		if !reflect.DeepEqual(ev, data[row]) {
+			b.Fatalf("row %d is %s, expected %s", row, ev, data[row])
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for row := 0; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					ev, err := nestedEventFromRow(values)
+					if err != nil {
+						b.Fatalf("row %d: %v", row, err)
+					}
+					check(b, row, ev)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var ev nestedEvent
+					if err := r.Scan(&ev); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, ev)
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var rec myNestedRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					check(b, row, nestedEvent(rec))
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(xitongsysNestedEvent), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]xitongsysNestedEvent, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					for _, r := range rec {
+						check(b, row, r.nested())
+						row++
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				for row := 0; ; row++ {
+					var ev nestedEvent
+					if err := r.Read(&ev); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					check(b, row, ev)
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				int64Values := make([]int64, batchSize)
+				int32Values := make([]int32, batchSize)
+				byteArrayValues := make([]parquet3.ByteArray, batchSize)
+				defLevels := make([]int16, batchSize)
+
+				// arrow reads column by column, so the events of a row group
+				// are assembled from all leaf columns before they are checked.
+				readInt64s := func(b *testing.B, col file.ColumnChunkReader, set func(idx int, v int64)) {
+					c, ok := col.(*file.Int64ColumnChunkReader)
+					if !ok {
+						b.Fatalf("couldn't assert %s column which is %T", col.Descriptor().Path(), col)
+					}
+
+					idx := 0
+					for c.HasNext() {
+						_, n, err := c.ReadBatch(int64(len(int64Values)), int64Values, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						for _, v := range int64Values[:n] {
+							set(idx, v)
+							idx++
+						}
+					}
+				}
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						row := 0
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							rgr := r.RowGroup(rg)
+
+							events := make([]nestedEvent, rgr.NumRows())
+
+							readInt64s(b, rgr.Column(0), func(idx int, v int64) { events[idx].ID = v })
+							readInt64s(b, rgr.Column(1), func(idx int, v int64) { events[idx].User.ID = v })
+
+							nameCol, ok := rgr.Column(2).(*file.ByteArrayColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert user.name column which is %T", rgr.Column(2))
+							}
+
+							idx := 0
+							for nameCol.HasNext() {
+								_, n, err := nameCol.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, nil, nil)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+								for _, v := range byteArrayValues[:n] {
+									events[idx].User.Name = string(v)
+									idx++
+								}
+							}
+
+							// a defined city means that the address is set.
+							cityCol, ok := rgr.Column(3).(*file.ByteArrayColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert user.address.city column which is %T", rgr.Column(3))
+							}
+
+							idx = 0
+							for cityCol.HasNext() {
+								numLevels, _, err := cityCol.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, defLevels, nil)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+								valueIdx := 0
+								for _, def := range defLevels[:numLevels] {
+									if def == 1 {
+										events[idx].User.Address = &nestedAddress{City: string(byteArrayValues[valueIdx])}
+										valueIdx++
+									}
+									idx++
+								}
+							}
+
+							zipCol, ok := rgr.Column(4).(*file.Int32ColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert user.address.zip column which is %T", rgr.Column(4))
+							}
+
+							idx = 0
+							for zipCol.HasNext() {
+								numLevels, _, err := zipCol.ReadBatch(int64(len(int32Values)), int32Values, defLevels, nil)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+								valueIdx := 0
+								for _, def := range defLevels[:numLevels] {
+									if def == 2 {
+										zip := int32Values[valueIdx]
+										events[idx].User.Address.Zip = &zip
+										valueIdx++
+									}
+									idx++
+								}
+							}
+
+							for _, ev := range events {
+								check(b, row, ev)
+								row++
+							}
+						}
+
+						if row != len(data) {
+							b.Fatalf("read %d rows, expected %d", row, len(data))
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						ids := rec.Column(0).(*array.Int64)
+						users := rec.Column(1).(*array.Struct)
+						userIDs := users.Field(0).(*array.Int64)
+						names := users.Field(1).(*array.String)
+						addresses := users.Field(2).(*array.Struct)
+						cities := addresses.Field(0).(*array.String)
+						zips := addresses.Field(1).(*array.Int32)
+
+						for j := 0; j < int(rec.NumRows()); j++ {
+							ev := nestedEvent{
+								ID: ids.Value(j),
+								User: nestedUser{
+									ID:   userIDs.Value(j),
+									Name: names.Value(j),
+								},
+							}
+							if addresses.IsValid(j) {
+								ev.User.Address = &nestedAddress{City: cities.Value(j)}
+								if zips.IsValid(j) {
+									zip := zips.Value(j)
+									ev.User.Address.Zip = &zip
+								}
+							}
+							check(b, row, ev)
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+
+					if row != len(data) {
+						b.Fatalf("read %d rows, expected %d", row, len(data))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				tr := array.NewTableReader(tbl, -1)
+				defer tr.Release()
+
+				row := 0
+				for tr.Next() {
+					rec := tr.Record()
+					ids := rec.Column(0).(*array.Int64)
+					users := rec.Column(1).(*array.Struct)
+					userIDs := users.Field(0).(*array.Int64)
+					names := users.Field(1).(*array.String)
+					addresses := users.Field(2).(*array.Struct)
+					cities := addresses.Field(0).(*array.String)
+					zips := addresses.Field(1).(*array.Int32)
+
+					for j := 0; j < int(rec.NumRows()); j++ {
+						ev := nestedEvent{
+							ID: ids.Value(j),
+							User: nestedUser{
+								ID:   userIDs.Value(j),
+								Name: names.Value(j),
+							},
+						}
+						if addresses.IsValid(j) {
+							ev.User.Address = &nestedAddress{City: cities.Value(j)}
+							if zips.IsValid(j) {
+								zip := zips.Value(j)
+								ev.User.Address.Zip = &zip
+							}
+						}
+						check(b, row, ev)
+						row++
+					}
+				}
+
+				if row != len(data) {
+					b.Fatalf("read %d rows, expected %d", row, len(data))
+				}
+			}()
+		}
+	})
+}
+
+// myNestedRecord has the same layout as nestedEvent, but brings its own
+// unmarshalling code instead of relying on reflection.
+type myNestedRecord nestedEvent
+
+func (r *myNestedRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	id, err := obj.GetField("id").Int64()
+	if err != nil {
+		return err
+	}
+	r.ID = id
+
+	user, err := obj.GetField("user").Group()
+	if err != nil {
+		return err
+	}
+
+	if r.User.ID, err = user.GetField("id").Int64(); err != nil {
+		return err
+	}
+
+	name, err := user.GetField("name").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.User.Name = string(name)
+
+	address, err := user.GetField("address").Group()
+	if err != nil {
+		// address is optional.
+		return nil
+	}
+
+	city, err := address.GetField("city").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.User.Address = &nestedAddress{City: string(city)}
+
+	if zip, err := address.GetField("zip").Int32(); err == nil {
+		r.User.Address.Zip = &zip
+	}
+
+	return nil
+}
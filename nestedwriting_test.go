@@ -0,0 +1,651 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const nestedSchema = `message event {
+	required int64 id;
+	required group user {
+		required int64 id;
+		required binary name (STRING);
+		optional group address {
+			required binary city (STRING);
+			optional int32 zip;
+		}
+	}
+}`
+
+// nestedEvent is the Go representation of nestedSchema. The struct tags
+// are understood by both floor and segmentio.
+type nestedEvent struct {
+	ID   int64      `parquet:"id"`
+	User nestedUser `parquet:"user"`
+}
+
+type nestedUser struct {
+	ID      int64          `parquet:"id"`
+	Name    string         `parquet:"name"`
+	Address *nestedAddress `parquet:"address"`
+}
+
+type nestedAddress struct {
+	City string `parquet:"city"`
+	Zip  *int32 `parquet:"zip"`
+}
+
+func (e nestedEvent) String() string {
+	address := "null"
+	if a := e.User.Address; a != nil {
+		zip := "null"
+		if a.Zip != nil {
+			zip = fmt.Sprint(*a.Zip)
+		}
+		address = fmt.Sprintf("{city=%s zip=%s}", a.City, zip)
+	}
+	return fmt.Sprintf("{id=%d user={id=%d name=%s address=%s}}", e.ID, e.User.ID, e.User.Name, address)
+}
+
+// The xitongsys equivalents of nestedEvent, which needs its own struct
+// tags.
+type xitongsysNestedEvent struct {
+	ID   int64               `parquet:"name=id, type=INT64"`
+	User xitongsysNestedUser `parquet:"name=user"`
+}
+
+type xitongsysNestedUser struct {
+	ID      int64                   `parquet:"name=id, type=INT64"`
+	Name    string                  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Address *xitongsysNestedAddress `parquet:"name=address, repetitiontype=OPTIONAL"`
+}
+
+type xitongsysNestedAddress struct {
+	City string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Zip  *int32 `parquet:"name=zip, type=INT32, repetitiontype=OPTIONAL"`
+}
+
+func (e nestedEvent) xitongsys() xitongsysNestedEvent {
+	rec := xitongsysNestedEvent{
+		ID: e.ID,
+		User: xitongsysNestedUser{
+			ID:   e.User.ID,
+			Name: e.User.Name,
+		},
+	}
+	if a := e.User.Address; a != nil {
+		rec.User.Address = &xitongsysNestedAddress{
+			City: a.City,
+			Zip:  a.Zip,
+		}
+	}
+	return rec
+}
+
+func (e xitongsysNestedEvent) nested() nestedEvent {
+	ev := nestedEvent{
+		ID: e.ID,
+		User: nestedUser{
+			ID:   e.User.ID,
+			Name: e.User.Name,
+		},
+	}
+	if a := e.User.Address; a != nil {
+		ev.User.Address = &nestedAddress{
+			City: a.City,
+			Zip:  a.Zip,
+		}
+	}
+	return ev
+}
+
+func nestedArrowSchema() (*schema.GroupNode, error) {
+	address, err := schema.NewGroupNode("address", parquet3.Repetitions.Optional, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("city", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+		schema.MustPrimitive(schema.NewPrimitiveNode("zip", parquet3.Repetitions.Optional, parquet3.Types.Int32, 0, 0)),
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := schema.NewGroupNode("user", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+		schema.MustPrimitive(schema.NewPrimitiveNodeLogical("name", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+		address,
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.NewGroupNode("event", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+		user,
+	}, 0)
+}
+
+// nestedPqarrowSchema is the arrow schema that pqarrow maps to
+// nestedSchema.
+func nestedPqarrowSchema() *arrow.Schema {
+	address := arrow.StructOf(
+		arrow.Field{Name: "city", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "zip", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	)
+
+	user := arrow.StructOf(
+		arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "address", Type: address, Nullable: true},
+	)
+
+	return arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "user", Type: user},
+	}, nil)
+}
+
+var nestedCities = []string{"Berlin", "Hamburg", "Munich", "Cologne", "Frankfurt", "Stuttgart", "Leipzig"}
+
+// generateNestedEvents returns n events. A quarter of the users come
+// without an address, and a third of the addresses without a zip code.
+func generateNestedEvents(n int) []nestedEvent {
+	data := make([]nestedEvent, n)
+	for i := range data {
+		userID := rand.Int63n(100000)
+		data[i] = nestedEvent{
+			ID: int64(i),
+			User: nestedUser{
+				ID:   userID,
+				Name: fmt.Sprintf("user%d", userID),
+			},
+		}
+		if rand.Intn(4) != 0 {
+			address := &nestedAddress{
+				City: nestedCities[rand.Intn(len(nestedCities))],
+			}
+			if rand.Intn(3) != 0 {
+				zip := rand.Int31n(100000)
+				address.Zip = &zip
+			}
+			data[i].User.Address = address
+		}
+	}
+	return data
+}
+
+// createNestedObject converts an event into the map representation used
+// by parquet-go's low-level API.
+func createNestedObject(ev nestedEvent) map[string]interface{} {
+	user := map[string]interface{}{
+		"id":   ev.User.ID,
+		"name": []byte(ev.User.Name),
+	}
+	if a := ev.User.Address; a != nil {
+		address := map[string]interface{}{
+			"city": []byte(a.City),
+		}
+		if a.Zip != nil {
+			address["zip"] = *a.Zip
+		}
+		user["address"] = address
+	}
+	return map[string]interface{}{
+		"id":   ev.ID,
+		"user": user,
+	}
+}
+
+// nestedEventFromRow is the inverse of createNestedObject.
+func nestedEventFromRow(row map[string]interface{}) (nestedEvent, error) {
+	var ev nestedEvent
+
+	id, ok := row["id"].(int64)
+	if !ok {
+		return ev, fmt.Errorf("unexpected id %T", row["id"])
+	}
+	ev.ID = id
+
+	user, ok := row["user"].(map[string]interface{})
+	if !ok {
+		return ev, fmt.Errorf("unexpected user %T", row["user"])
+	}
+
+	if ev.User.ID, ok = user["id"].(int64); !ok {
+		return ev, fmt.Errorf("unexpected user.id %T", user["id"])
+	}
+
+	name, ok := user["name"].([]byte)
+	if !ok {
+		return ev, fmt.Errorf("unexpected user.name %T", user["name"])
+	}
+	ev.User.Name = string(name)
+
+	address, ok := user["address"].(map[string]interface{})
+	if !ok {
+		return ev, nil
+	}
+
+	city, ok := address["city"].([]byte)
+	if !ok {
+		return ev, fmt.Errorf("unexpected user.address.city %T", address["city"])
+	}
+	ev.User.Address = &nestedAddress{City: string(city)}
+
+	if zip, ok := address["zip"].(int32); ok {
+		ev.User.Address.Zip = &zip
+	}
+
+	return ev, nil
+}
+
+// verifyNestedFile reads back a file and checks that every event, including
+// the missing addresses and zip codes, survived the round trip unchanged.
+func verifyNestedFile(b *testing.B, filename string, data []nestedEvent) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(data) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(data))
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+
+		ev, err := nestedEventFromRow(row)
+		if err != nil {
+			b.Fatalf("%s: row %d: %v", filename, i, err)
+		}
+		if !reflect.DeepEqual(ev, data[i]) {
+			b.Fatalf("%s: row %d is %s, expected %s", filename, i, ev, data[i])
+		}
+	}
+}
+
+func BenchmarkNestedWriting(b *testing.B) {
+	prefix := "nestedwr_"
+
+	data := generateNestedEvents(100000)
+
+	b.ResetTimer()
+
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(nestedSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, ev := range data {
+					if err = fw.Write(ev); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyNestedFile(b, parquetFilename, data)
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(nestedSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				for _, ev := range data {
+					r := nestedRecord(ev)
+					if err = fw.Write(&r); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyNestedFile(b, parquetFilename, data)
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				schemaDef, err := parquetschema.ParseSchemaDefinition(nestedSchema)
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				for _, ev := range data {
+					if err = fw.AddData(createNestedObject(ev)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyNestedFile(b, parquetFilename, data)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				//write
+				pw, err := writer.NewParquetWriterFromWriter(w, new(xitongsysNestedEvent), 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				for _, ev := range data {
+					if err = pw.Write(ev.xitongsys()); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+
+		b.StopTimer()
+		verifyNestedFile(b, filename, data)
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := nestedArrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						// arrow writes column by column, so the events are
+						// shredded into one slice of values per leaf column
+						// first. The optional fields need definition levels:
+						// city is defined if there is an address, zip needs
+						// both the address and the zip code.
+						var (
+							ids      = make([]int64, len(data))
+							userIDs  = make([]int64, len(data))
+							names    = make([]parquet3.ByteArray, len(data))
+							cities   = make([]parquet3.ByteArray, 0, len(data))
+							cityDefs = make([]int16, len(data))
+							zips     = make([]int32, 0, len(data))
+							zipDefs  = make([]int16, len(data))
+						)
+
+						for i, ev := range data {
+							ids[i] = ev.ID
+							userIDs[i] = ev.User.ID
+							names[i] = parquet3.ByteArray(ev.User.Name)
+							if a := ev.User.Address; a != nil {
+								cities = append(cities, parquet3.ByteArray(a.City))
+								cityDefs[i] = 1
+								zipDefs[i] = 1
+								if a.Zip != nil {
+									zips = append(zips, *a.Zip)
+									zipDefs[i] = 2
+								}
+							}
+						}
+
+						rg := pw.AppendRowGroup()
+
+						for _, write := range []func(col file.ColumnChunkWriter) error{
+							func(col file.ColumnChunkWriter) error {
+								return writeBatches(len(ids), batchSize, func(start, end int) error {
+									_, err := col.(*file.Int64ColumnChunkWriter).WriteBatch(ids[start:end], nil, nil)
+									return err
+								})
+							},
+							func(col file.ColumnChunkWriter) error {
+								return writeBatches(len(userIDs), batchSize, func(start, end int) error {
+									_, err := col.(*file.Int64ColumnChunkWriter).WriteBatch(userIDs[start:end], nil, nil)
+									return err
+								})
+							},
+							func(col file.ColumnChunkWriter) error {
+								return writeBatches(len(names), batchSize, func(start, end int) error {
+									_, err := col.(*file.ByteArrayColumnChunkWriter).WriteBatch(names[start:end], nil, nil)
+									return err
+								})
+							},
+							func(col file.ColumnChunkWriter) error {
+								return writeLeveledBatches(cityDefs, nil, 1, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+									_, err := col.(*file.ByteArrayColumnChunkWriter).WriteBatch(cities[valueStart:valueEnd], cityDefs[levelStart:levelEnd], nil)
+									return err
+								})
+							},
+							func(col file.ColumnChunkWriter) error {
+								return writeLeveledBatches(zipDefs, nil, 2, batchSize, func(levelStart, levelEnd, valueStart, valueEnd int) error {
+									_, err := col.(*file.Int32ColumnChunkWriter).WriteBatch(zips[valueStart:valueEnd], zipDefs[levelStart:levelEnd], nil)
+									return err
+								})
+							},
+						} {
+							col, err := rg.NextColumn()
+							if err != nil {
+								b.Fatalf("NextColumn failed: %v", err)
+							}
+
+							if err := write(col); err != nil {
+								b.Fatalf("WriteBatch failed: %v", err)
+							}
+
+							col.Close()
+						}
+
+						defer rg.Close()
+					}()
+				}
+
+				b.StopTimer()
+				verifyNestedFile(b, filename, data)
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := nestedPqarrowSchema()
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				idBld := bld.Field(0).(*array.Int64Builder)
+				userBld := bld.Field(1).(*array.StructBuilder)
+				userIDBld := userBld.FieldBuilder(0).(*array.Int64Builder)
+				nameBld := userBld.FieldBuilder(1).(*array.StringBuilder)
+				addressBld := userBld.FieldBuilder(2).(*array.StructBuilder)
+				cityBld := addressBld.FieldBuilder(0).(*array.StringBuilder)
+				zipBld := addressBld.FieldBuilder(1).(*array.Int32Builder)
+
+				for _, ev := range data {
+					idBld.Append(ev.ID)
+					userBld.Append(true)
+					userIDBld.Append(ev.User.ID)
+					nameBld.Append(ev.User.Name)
+
+					a := ev.User.Address
+					if a == nil {
+						// also appends nulls to city and zip.
+						addressBld.AppendNull()
+						continue
+					}
+					addressBld.Append(true)
+					cityBld.Append(a.City)
+					if a.Zip != nil {
+						zipBld.Append(*a.Zip)
+					} else {
+						zipBld.AppendNull()
+					}
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyNestedFile(b, filename, data)
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(nestedEvent)), parquet4.Compression(&snappy.Codec{}))
+
+				for i := range data {
+					if err := wr.Write(&data[i]); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyNestedFile(b, parquetFilename, data)
+	})
+}
+
+// nestedRecord has the same layout as nestedEvent, but brings its own
+// marshalling code instead of relying on reflection.
+type nestedRecord nestedEvent
+
+func (r *nestedRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("id").SetInt64(r.ID)
+
+	user := obj.AddField("user").Group()
+	user.AddField("id").SetInt64(r.User.ID)
+	user.AddField("name").SetByteArray([]byte(r.User.Name))
+
+	if a := r.User.Address; a != nil {
+		address := user.AddField("address").Group()
+		address.AddField("city").SetByteArray([]byte(a.City))
+		if a.Zip != nil {
+			address.AddField("zip").SetInt32(*a.Zip)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package benchmark_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	parquet4 "github.com/segmentio/parquet-go"
+)
+
+// pageIndexLookups is the number of different random lookups that the
+// page index reading benchmarks cycle through.
+const pageIndexLookups = 1024
+
+func BenchmarkPageIndexReading(b *testing.B) {
+	ids := generatePushdownIDs(pageIndexRows)
+
+	parquetFilename := "pageindexrd_testdata.parquet"
+
+	if err := writeSegmentioPageIndexFile(parquetFilename, ids, len(ids)); err != nil {
+		b.Fatalf("Writing %s failed: %v", parquetFilename, err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("point", func(b *testing.B) {
+		benchmarkPageIndexReading(b, parquetFilename, ids, 1)
+	})
+
+	b.Run("range", func(b *testing.B) {
+		benchmarkPageIndexReading(b, parquetFilename, ids, 100)
+	})
+}
+
+// benchmarkPageIndexReading looks up the rows whose ids lie in a range
+// that covers numRows rows at a random position in the file. The file is
+// only opened once, so that the lookups themselves are measured.
+func benchmarkPageIndexReading(b *testing.B, parquetFilename string, ids []int64, numRows int) {
+	firsts := make([]int, pageIndexLookups)
+	for i := range firsts {
+		firsts[i] = rand.Intn(len(ids) - numRows)
+	}
+
+	// match checks a row against the range of lookup i and, if it
+	// matches, that it is the next expected row.
+	match := func(b *testing.B, i int, next *int, id int64, v float64) {
+		first := firsts[i%len(firsts)]
+		if id < ids[first] || id > ids[first+numRows-1] {
+			return
+		}
+		if *next >= first+numRows {
+			b.Fatalf("found more than the %d matching rows", numRows)
+		}
+		if id != ids[*next] {
+			b.Fatalf("matching row %d has id %d, expected %d", *next-first, id, ids[*next])
+		}
+		if v != pushdownValue(id) {
+			b.Fatalf("row with id %d has value %f, expected %f", id, v, pushdownValue(id))
+		}
+		*next++
+	}
+
+	checkMatches := func(b *testing.B, i int, next int) {
+		first := firsts[i%len(firsts)]
+		if next != first+numRows {
+			b.Fatalf("found %d matching rows, expected %d", next-first, numRows)
+		}
+	}
+
+	report := func(b *testing.B, bytesRead int64) {
+		b.ReportMetric(float64(bytesRead)/float64(b.N), "bytes-read/op")
+	}
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		b.Skip("parquet-go doesn't read page indexes")
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		b.Skip("xitongsys writes page indexes but doesn't use them for reading")
+	})
+
+	// segmentioLookups uses the column index of the id column to find the
+	// first page that can contain a match, and seeks to it using the
+	// offset index. Without page indexes, it reads all rows from the start
+	// of the row group instead.
+	segmentioLookups := func(b *testing.B, options ...parquet4.FileOption) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil {
+			b.Fatalf("Stat failed: %v", err)
+		}
+
+		pf, err := parquet4.OpenFile(f, stat.Size(), options...)
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		var row parquet4.Row
+
+		for i := 0; i < b.N; i++ {
+			first := firsts[i%len(firsts)]
+			lo, hi := ids[first], ids[first+numRows-1]
+			next := first
+
+		rowGroups:
+			for g := 0; g < pf.NumRowGroups(); g++ {
+				rg := pf.RowGroup(g)
+				rows := rg.Rows()
+
+				if ci, oi := rg.Column(0).ColumnIndex(), rg.Column(0).OffsetIndex(); ci != nil && oi != nil {
+					page := sort.Search(ci.NumPages(), func(i int) bool {
+						return ci.MaxValue(i).Int64() >= lo
+					})
+					if page == ci.NumPages() {
+						continue
+					}
+					if err := rows.SeekToRow(oi.FirstRowIndex(page)); err != nil {
+						b.Fatalf("SeekToRow failed: %v", err)
+					}
+				}
+
+				for {
+					row, err = rows.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					id := row[0].Int64()
+					if id > hi {
+						break rowGroups
+					}
+					match(b, i, &next, id, row[1].Double())
+				}
+			}
+			checkMatches(b, i, next)
+		}
+
+		report(b, bytesRead)
+	}
+
+	b.Run("segmentio", func(b *testing.B) {
+		segmentioLookups(b)
+	})
+
+	b.Run("segmentio_without_page_index", func(b *testing.B) {
+		segmentioLookups(b, parquet4.SkipPageIndex(true))
+	})
+
+	// arrow doesn't read page indexes, so it reads the columns until it
+	// finds the end of the range.
+	b.Run("apache_arrow", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		r, err := file.NewParquetReader(f)
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		idValues := make([]int64, 1024)
+		valueValues := make([]float64, 1024)
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			first := firsts[i%len(firsts)]
+			hi := ids[first+numRows-1]
+			next := first
+
+		rowGroups:
+			for rg := 0; rg < r.NumRowGroups(); rg++ {
+				rgr := r.RowGroup(rg)
+				idReader := rgr.Column(0).(*file.Int64ColumnChunkReader)
+				valueReader := rgr.Column(1).(*file.Float64ColumnChunkReader)
+
+				for idReader.HasNext() {
+					_, n, err := idReader.ReadBatch(int64(len(idValues)), idValues, nil, nil)
+					if err != nil {
+						b.Fatalf("ReadBatch failed: %v", err)
+					}
+					_, m, err := valueReader.ReadBatch(int64(n), valueValues, nil, nil)
+					if err != nil {
+						b.Fatalf("ReadBatch failed: %v", err)
+					}
+					if m != n {
+						b.Fatalf("read %d ids but %d values", n, m)
+					}
+					for j := 0; j < n; j++ {
+						if idValues[j] > hi {
+							break rowGroups
+						}
+						match(b, i, &next, idValues[j], valueValues[j])
+					}
+				}
+			}
+			checkMatches(b, i, next)
+		}
+
+		report(b, bytesRead)
+	})
+
+	// pqarrow doesn't read page indexes either, and its record reader
+	// can't be rewound, so a new one reads each lookup from the start.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		r, err := file.NewParquetReader(f)
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{BatchSize: 1024}, memory.DefaultAllocator)
+		if err != nil {
+			b.Fatalf("Creating pqarrow reader failed: %v", err)
+		}
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			first := firsts[i%len(firsts)]
+			hi := ids[first+numRows-1]
+			next := first
+
+			rr, err := fr.GetRecordReader(context.Background(), nil, nil)
+			if err != nil {
+				b.Fatalf("Creating record reader failed: %v", err)
+			}
+
+		records:
+			for rr.Next() {
+				rec := rr.Record()
+				idValues := rec.Column(0).(*array.Int64).Int64Values()
+				valueValues := rec.Column(1).(*array.Float64).Float64Values()
+				for j, id := range idValues {
+					if id > hi {
+						break records
+					}
+					match(b, i, &next, id, valueValues[j])
+				}
+			}
+			rr.Release()
+
+			checkMatches(b, i, next)
+		}
+
+		report(b, bytesRead)
+	})
+}
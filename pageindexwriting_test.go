@@ -0,0 +1,380 @@
+package benchmark_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// pageIndexRows sorted ids are written to the page index files, in pages
+// of at most pageIndexPageSize bytes so that there are many pages to
+// index.
+const (
+	pageIndexRows     = 1000000
+	pageIndexPageSize = 64 * 1024
+)
+
+const pageIndexSchema = `message pageindex {
+	required int64 id;
+	required double value;
+}`
+
+const xitongsysPageIndexSchema = `{
+	"Tag": "name=pageindex, repetitiontype=REQUIRED",
+	"Fields": [
+		{"Tag": "name=id, inname=ID, type=INT64, repetitiontype=REQUIRED"},
+		{"Tag": "name=value, inname=Value, type=DOUBLE, repetitiontype=REQUIRED"}
+	]
+}`
+
+// countPageIndexes returns the number of column chunks in a file and how
+// many of them have a column index and an offset index.
+func countPageIndexes(filename string) (chunks, columnIndexes, offsetIndexes int, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer f.Close()
+
+	meta, err := goparquet.ReadFileMetaData(f, false)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	for _, rg := range meta.RowGroups {
+		for _, col := range rg.Columns {
+			chunks++
+			if col.ColumnIndexOffset != nil {
+				columnIndexes++
+			}
+			if col.OffsetIndexOffset != nil {
+				offsetIndexes++
+			}
+		}
+	}
+
+	return chunks, columnIndexes, offsetIndexes, nil
+}
+
+// reportPageIndexes reports the share of column chunks in a file that have
+// a column index and an offset index, which is 0 for libraries that don't
+// write page indexes at all.
+func reportPageIndexes(b *testing.B, filename string) {
+	chunks, columnIndexes, offsetIndexes, err := countPageIndexes(filename)
+	if err != nil {
+		b.Fatalf("%s: reading page indexes failed: %v", filename, err)
+	}
+	b.ReportMetric(float64(columnIndexes)/float64(chunks), "column-index/chunk")
+	b.ReportMetric(float64(offsetIndexes)/float64(chunks), "offset-index/chunk")
+}
+
+func verifyPageIndexFile(b *testing.B, filename string, ids []int64) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(ids) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(ids))
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+
+		if row["id"] != ids[i] || row["value"] != pushdownValue(ids[i]) {
+			b.Fatalf("%s: row %d is %v/%v, expected %d/%f", filename, i, row["id"], row["value"], ids[i], pushdownValue(ids[i]))
+		}
+	}
+}
+
+func BenchmarkPageIndexWriting(b *testing.B) {
+	ids := generatePushdownIDs(pageIndexRows)
+
+	schemaDef, err := parquetschema.ParseSchemaDefinition(pageIndexSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	prefix := "pageindexwr_"
+
+	b.ResetTimer()
+
+	// floor writes through the low-level writer, so it isn't benchmarked
+	// separately.
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		filename := prefix + "parquet_go_lowlevel.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", filename, err)
+				}
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+					goparquet.WithMaxPageSize(pageIndexPageSize),
+				)
+
+				for _, id := range ids {
+					if err := fw.AddData(map[string]interface{}{"id": id, "value": pushdownValue(id)}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyPageIndexFile(b, filename, ids)
+		reportPageIndexes(b, filename)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+				defer w.Close()
+
+				pw, err := writer.NewParquetWriterFromWriter(w, xitongsysPageIndexSchema, 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+				pw.PageSize = pageIndexPageSize
+
+				for _, id := range ids {
+					if err = pw.Write(pushdownRecord{ID: id, Value: pushdownValue(id)}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyPageIndexFile(b, filename, ids)
+		reportPageIndexes(b, filename)
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				values := make([]float64, len(ids))
+				for i, id := range ids {
+					values[i] = pushdownValue(id)
+				}
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer w.Close()
+
+						sc, err := schema.NewGroupNode("pageindex", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+							schema.MustPrimitive(schema.NewPrimitiveNode("value", parquet3.Repetitions.Required, parquet3.Types.Double, 0, 0)),
+						}, 0)
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(
+							parquet3.WithCompression(compress.Codecs.Snappy),
+							parquet3.WithDataPageSize(pageIndexPageSize),
+						)))
+						defer pw.Close()
+
+						rg := pw.AppendRowGroup()
+						defer rg.Close()
+
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						idCol, ok := col.(*file.Int64ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert id column which is %T", col)
+						}
+
+						if err := writeBatches(len(ids), batchSize, func(start, end int) error {
+							_, err := idCol.WriteBatch(ids[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						idCol.Close()
+
+						col, err = rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
+
+						valueCol, ok := col.(*file.Float64ColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert value column which is %T", col)
+						}
+
+						if err := writeBatches(len(values), batchSize, func(start, end int) error {
+							_, err := valueCol.WriteBatch(values[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						valueCol.Close()
+					}()
+				}
+
+				b.StopTimer()
+				verifyPageIndexFile(b, filename, ids)
+				reportPageIndexes(b, filename)
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		sc := arrow.NewSchema([]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+		}, nil)
+
+		values := make([]float64, len(ids))
+		for i, id := range ids {
+			values[i] = pushdownValue(id)
+		}
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer w.Close()
+
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+				bld.Field(1).(*array.Float64Builder).AppendValues(values, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				fw, err := pqarrow.NewFileWriter(sc, w,
+					parquet3.NewWriterProperties(
+						parquet3.WithCompression(compress.Codecs.Snappy),
+						parquet3.WithDataPageSize(pageIndexPageSize),
+					),
+					pqarrow.DefaultWriterProps(),
+				)
+				if err != nil {
+					b.Fatalf("Creating pqarrow writer failed: %v", err)
+				}
+
+				if err := fw.Write(rec); err != nil {
+					b.Fatalf("Write failed: %v", err)
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing pqarrow writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyPageIndexFile(b, filename, ids)
+		reportPageIndexes(b, filename)
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		filename := prefix + "segmentio_parquet_go.parquet"
+
+		for n := 0; n < b.N; n++ {
+			if err := writeSegmentioPageIndexFile(filename, ids, len(ids)); err != nil {
+				b.Fatalf("Writing %s failed: %v", filename, err)
+			}
+		}
+
+		b.StopTimer()
+		verifyPageIndexFile(b, filename, ids)
+		reportPageIndexes(b, filename)
+	})
+}
+
+// writeSegmentioPageIndexFile writes the ids with segmentio, which always
+// writes page indexes, in row groups of rowGroupSize rows.
+func writeSegmentioPageIndexFile(filename string, ids []int64, rowGroupSize int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(pushdownRecord)),
+		parquet4.Compression(&snappy.Codec{}),
+		parquet4.PageBufferSize(pageIndexPageSize),
+	)
+
+	for i, id := range ids {
+		if err := wr.Write(&pushdownRecord{ID: id, Value: pushdownValue(id)}); err != nil {
+			return err
+		}
+		if (i+1)%rowGroupSize == 0 {
+			if err := wr.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return wr.Close()
+}
@@ -0,0 +1,381 @@
+package benchmark_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parallelRows is the number of rows in each file written by the parallel
+// writing benchmarks.
+const parallelRows = 100000
+
+const parallelWritingSchema = `message parallel {
+	required int64 id;
+	required binary name (STRING);
+	required double value;
+}`
+
+const xitongsysParallelWritingSchema = `{
+	"Tag": "name=parallel, repetitiontype=REQUIRED",
+	"Fields": [
+		{"Tag": "name=id, inname=ID, type=INT64, repetitiontype=REQUIRED"},
+		{"Tag": "name=name, inname=Name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+		{"Tag": "name=value, inname=Value, type=DOUBLE, repetitiontype=REQUIRED"}
+	]
+}`
+
+type parallelRecord struct {
+	ID    int64   `parquet:"id"`
+	Name  string  `parquet:"name"`
+	Value float64 `parquet:"value"`
+}
+
+func generateParallelRecords(n int) []parallelRecord {
+	data := make([]parallelRecord, n)
+	for i := range data {
+		data[i] = parallelRecord{
+			ID:    rand.Int63(),
+			Name:  fmt.Sprintf("name %d", rand.Intn(1000)),
+			Value: rand.NormFloat64(),
+		}
+	}
+	return data
+}
+
+func verifyParallelFile(b *testing.B, filename string, data []parallelRecord) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("%s: reading parquet file failed: %v", filename, err)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(data) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(data))
+				}
+				break
+			}
+			b.Fatalf("%s: NextRow returned error: %v", filename, err)
+		}
+
+		name, _ := row["name"].([]byte)
+		if row["id"] != data[i].ID || string(name) != data[i].Name || row["value"] != data[i].Value {
+			b.Fatalf("%s: row %d is %v/%q/%v, expected %d/%q/%f", filename, i, row["id"], name, row["value"], data[i].ID, data[i].Name, data[i].Value)
+		}
+	}
+}
+
+// BenchmarkParallelWriting writes a separate file from each goroutine of
+// b.RunParallel. Running it with different -cpu values shows how well the
+// libraries scale, and -mutexprofile shows where they contend on shared
+// state. Every file is verified afterwards, so that state shared between
+// writers that corrupts files is noticed.
+func BenchmarkParallelWriting(b *testing.B) {
+	data := generateParallelRecords(parallelRows)
+
+	schemaDef, err := parquetschema.ParseSchemaDefinition(parallelWritingSchema)
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	prefix := "parallelwr_"
+
+	b.ResetTimer()
+
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		type record struct {
+			ID    int64   `parquet:"id"`
+			Name  string  `parquet:"name"`
+			Value float64 `parquet:"value"`
+		}
+
+		benchmarkParallelWriting(b, prefix+"parquet_go_floor_reflection", data, func(filename string) error {
+			fw, err := floor.NewFileWriter(filename,
+				goparquet.WithSchemaDefinition(schemaDef),
+				goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, rec := range data {
+				if err := fw.Write(record(rec)); err != nil {
+					return err
+				}
+			}
+
+			return fw.Close()
+		})
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		benchmarkParallelWriting(b, prefix+"parquet_go_floor_marshalling", data, func(filename string) error {
+			fw, err := floor.NewFileWriter(filename,
+				goparquet.WithSchemaDefinition(schemaDef),
+				goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, rec := range data {
+				if err := fw.Write(parallelMarshalRecord(rec)); err != nil {
+					return err
+				}
+			}
+
+			return fw.Close()
+		})
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		benchmarkParallelWriting(b, prefix+"parquet_go_lowlevel", data, func(filename string) error {
+			w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+
+			fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+				goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+			for _, rec := range data {
+				if err := fw.AddData(map[string]interface{}{"id": rec.ID, "name": []byte(rec.Name), "value": rec.Value}); err != nil {
+					return err
+				}
+			}
+
+			return fw.Close()
+		})
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		benchmarkParallelWriting(b, prefix+"xitongsys_parquet_go", data, func(filename string) error {
+			w, err := os.Create(filename)
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+
+			pw, err := writer.NewParquetWriterFromWriter(w, xitongsysParallelWritingSchema, 4)
+			if err != nil {
+				return err
+			}
+
+			pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+			for _, rec := range data {
+				if err := pw.Write(rec); err != nil {
+					return err
+				}
+			}
+
+			return pw.WriteStop()
+		})
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		ids := make([]int64, len(data))
+		names := make([]parquet3.ByteArray, len(data))
+		values := make([]float64, len(data))
+		for i, rec := range data {
+			ids[i], names[i], values[i] = rec.ID, parquet3.ByteArray(rec.Name), rec.Value
+		}
+
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				benchmarkParallelWriting(b, prefix+"apache_arrow_parquet", data, func(filename string) error {
+					w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+					if err != nil {
+						return err
+					}
+					defer w.Close()
+
+					sc, err := schema.NewGroupNode("parallel", parquet3.Repetitions.Required, schema.FieldList{
+						schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+						schema.MustPrimitive(schema.NewPrimitiveNodeLogical("name", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+						schema.MustPrimitive(schema.NewPrimitiveNode("value", parquet3.Repetitions.Required, parquet3.Types.Double, 0, 0)),
+					}, 0)
+					if err != nil {
+						return err
+					}
+
+					pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+
+					rg := pw.AppendRowGroup()
+
+					for c := 0; c < sc.NumFields(); c++ {
+						col, err := rg.NextColumn()
+						if err != nil {
+							return err
+						}
+
+						switch col := col.(type) {
+						case *file.Int64ColumnChunkWriter:
+							err = writeBatches(len(ids), batchSize, func(start, end int) error {
+								_, err := col.WriteBatch(ids[start:end], nil, nil)
+								return err
+							})
+						case *file.ByteArrayColumnChunkWriter:
+							err = writeBatches(len(names), batchSize, func(start, end int) error {
+								_, err := col.WriteBatch(names[start:end], nil, nil)
+								return err
+							})
+						case *file.Float64ColumnChunkWriter:
+							err = writeBatches(len(values), batchSize, func(start, end int) error {
+								_, err := col.WriteBatch(values[start:end], nil, nil)
+								return err
+							})
+						default:
+							return fmt.Errorf("unexpected column writer %T", col)
+						}
+						if err != nil {
+							return err
+						}
+
+						if err := col.Close(); err != nil {
+							return err
+						}
+					}
+
+					if err := rg.Close(); err != nil {
+						return err
+					}
+
+					return pw.Close()
+				})
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		sc := arrow.NewSchema([]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+			{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+		}, nil)
+
+		benchmarkParallelWriting(b, prefix+"apache_arrow_pqarrow", data, func(filename string) error {
+			bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+			defer bld.Release()
+
+			idBld := bld.Field(0).(*array.Int64Builder)
+			nameBld := bld.Field(1).(*array.StringBuilder)
+			valueBld := bld.Field(2).(*array.Float64Builder)
+
+			for _, rec := range data {
+				idBld.Append(rec.ID)
+				nameBld.Append(rec.Name)
+				valueBld.Append(rec.Value)
+			}
+
+			rec := bld.NewRecord()
+			defer rec.Release()
+
+			return writePqarrowRecord(filename, rec)
+		})
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		benchmarkParallelWriting(b, prefix+"segmentio_parquet_go", data, func(filename string) error {
+			f, err := os.Create(filename)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			wr := parquet4.NewWriter(f, parquet4.SchemaOf(new(parallelRecord)), parquet4.Compression(&snappy.Codec{}))
+
+			for i := range data {
+				if err := wr.Write(&data[i]); err != nil {
+					return err
+				}
+			}
+
+			return wr.Close()
+		})
+	})
+}
+
+// benchmarkParallelWriting calls write from all goroutines of b.RunParallel,
+// each with a file name of its own, and verifies all written files.
+func benchmarkParallelWriting(b *testing.B, prefix string, data []parallelRecord, write func(filename string) error) {
+	var (
+		workers int32
+
+		mtx       sync.Mutex
+		filenames []string
+	)
+
+	b.RunParallel(func(pb *testing.PB) {
+		filename := fmt.Sprintf("%s_%d.parquet", prefix, atomic.AddInt32(&workers, 1))
+
+		written := false
+		for pb.Next() {
+			if err := write(filename); err != nil {
+				// Fatalf must not be called from the goroutines of
+				// RunParallel.
+				b.Errorf("Writing %s failed: %v", filename, err)
+				return
+			}
+			written = true
+		}
+
+		// goroutines that got no iterations haven't written a file.
+		if written {
+			mtx.Lock()
+			filenames = append(filenames, filename)
+			mtx.Unlock()
+		}
+	})
+
+	b.StopTimer()
+
+	if b.Failed() {
+		return
+	}
+
+	for _, filename := range filenames {
+		verifyParallelFile(b, filename, data)
+	}
+}
+
+type parallelMarshalRecord parallelRecord
+
+func (r parallelMarshalRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	obj.AddField("id").SetInt64(r.ID)
+	obj.AddField("name").SetByteArray([]byte(r.Name))
+	obj.AddField("value").SetFloat64(r.Value)
+	return nil
+}
@@ -0,0 +1,492 @@
+package benchmark_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// The wide file that the projection benchmarks select columns from.
+const (
+	projectionColumns = 200
+	projectionRows    = 20000
+)
+
+// projectionPercentages are the shares of columns that are read.
+var projectionPercentages = []int{1, 5, 10}
+
+// projectedColumns returns n columns spread evenly across a schema with
+// numColumns columns, shifted so that they cycle through all column
+// types.
+func projectedColumns(numColumns, n int) []int {
+	step := numColumns / n
+	cols := make([]int, n)
+	for i := range cols {
+		cols[i] = i*step + i%numWideTypes
+	}
+	return cols
+}
+
+// countingFile counts the bytes that libraries read from a file,
+// regardless of whether they use it as an io.ReadSeeker, an io.ReaderAt
+// or a xitongsys source.ParquetFile.
+type countingFile struct {
+	*os.File
+	bytesRead *int64
+}
+
+func openCountingFile(filename string, bytesRead *int64) (*countingFile, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &countingFile{File: f, bytesRead: bytesRead}, nil
+}
+
+func (f *countingFile) Read(p []byte) (int, error) {
+	n, err := f.File.Read(p)
+	atomic.AddInt64(f.bytesRead, int64(n))
+	return n, err
+}
+
+func (f *countingFile) ReadAt(p []byte, off int64) (int, error) {
+	n, err := f.File.ReadAt(p, off)
+	atomic.AddInt64(f.bytesRead, int64(n))
+	return n, err
+}
+
+// Open opens another handle of the file, which xitongsys does for every
+// column it reads. The bytes read through it are counted as well.
+func (f *countingFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.Name()
+	}
+	return openCountingFile(name, f.bytesRead)
+}
+
+func (f *countingFile) Create(name string) (source.ParquetFile, error) {
+	return nil, errors.New("countingFile is read-only")
+}
+
+func BenchmarkProjectionReading(b *testing.B) {
+	t := generateWideTable(projectionColumns, projectionRows)
+
+	schemaDef, err := parquetschema.ParseSchemaDefinition(t.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := "projectionrd_testdata.parquet"
+
+	w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+	}
+
+	fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+	for i := range t.rows {
+		if err = fw.AddData(t.object(i)); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+	w.Close()
+
+	stat, err := os.Stat(parquetFilename)
+	if err != nil {
+		b.Fatalf("Stat failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	for _, pct := range projectionPercentages {
+		cols := projectedColumns(t.numColumns, t.numColumns*pct/100)
+
+		b.Run(fmt.Sprintf("%dpct", pct), func(b *testing.B) {
+			benchmarkProjectionReading(b, parquetFilename, stat.Size(), t, cols)
+		})
+	}
+}
+
+// benchmarkProjectionReading reads only the given columns and reports how
+// many bytes each library actually read from the file to do so.
+func benchmarkProjectionReading(b *testing.B, parquetFilename string, fileSize int64, t wideTable, cols []int) {
+	// expected returns the value that was written to the given cell. The
+	// typed checks below assert it to the type they were read as, so that
+	// checking a value doesn't box it.
+	expected := func(b *testing.B, col, row int) interface{} {
+		if row >= len(t.rows) {
+			b.Fatalf("read more than the %d rows that were written", len(t.rows))
+		}
+		return t.rows[row][col]
+	}
+
+	fail := func(b *testing.B, col, row int, v interface{}) {
+		b.Fatalf("row %d column %s is %v (%T), expected %v (%T)", row, wideColumnName(col), v, v, t.rows[row][col], t.rows[row][col])
+	}
+
+	checkInt32 := func(b *testing.B, col, row int, v int32) {
+		if want, ok := expected(b, col, row).(int32); !ok || v != want {
+			fail(b, col, row, v)
+		}
+	}
+
+	checkInt64 := func(b *testing.B, col, row int, v int64) {
+		if want, ok := expected(b, col, row).(int64); !ok || v != want {
+			fail(b, col, row, v)
+		}
+	}
+
+	checkFloat64 := func(b *testing.B, col, row int, v float64) {
+		if want, ok := expected(b, col, row).(float64); !ok || v != want {
+			fail(b, col, row, v)
+		}
+	}
+
+	checkBool := func(b *testing.B, col, row int, v bool) {
+		if want, ok := expected(b, col, row).(bool); !ok || v != want {
+			fail(b, col, row, v)
+		}
+	}
+
+	checkString := func(b *testing.B, col, row int, v string) {
+		if want, ok := expected(b, col, row).(string); !ok || v != want {
+			fail(b, col, row, v)
+		}
+	}
+
+	checkBytes := func(b *testing.B, col, row int, v []byte) {
+		if want, ok := expected(b, col, row).(string); !ok || string(v) != want {
+			fail(b, col, row, string(v))
+		}
+	}
+
+	// checkValue checks a value that a library returned as an interface{}.
+	checkValue := func(b *testing.B, col, row int, v interface{}) {
+		switch v := v.(type) {
+		case int32:
+			checkInt32(b, col, row, v)
+		case int64:
+			checkInt64(b, col, row, v)
+		case float64:
+			checkFloat64(b, col, row, v)
+		case bool:
+			checkBool(b, col, row, v)
+		case string:
+			checkString(b, col, row, v)
+		case []byte:
+			checkBytes(b, col, row, v)
+		default:
+			fail(b, col, row, v)
+		}
+	}
+
+	checkRows := func(b *testing.B, col, numRows int) {
+		if numRows != len(t.rows) {
+			b.Fatalf("read %d rows of column %s, expected %d", numRows, wideColumnName(col), len(t.rows))
+		}
+	}
+
+	report := func(b *testing.B, bytesRead int64) {
+		b.ReportMetric(float64(bytesRead)/float64(b.N), "bytes-read/op")
+		b.ReportMetric(float64(fileSize), "file-bytes")
+	}
+
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = wideColumnName(col)
+	}
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f, names...)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				row := 0
+				for ; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					if len(values) != len(cols) {
+						b.Fatalf("row %d has %d columns, expected %d", row, len(values), len(cols))
+					}
+					for idx, col := range cols {
+						checkValue(b, col, row, values[names[idx]])
+					}
+				}
+				checkRows(b, cols[0], row)
+			}()
+		}
+
+		report(b, bytesRead)
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+				defer f.Close()
+
+				pr, err := reader.NewParquetColumnReader(f, 1)
+				if err != nil {
+					b.Fatalf("Creating parquet column reader failed: %v", err)
+				}
+
+				for _, col := range cols {
+					values, _, _, err := pr.ReadColumnByPath(common.ReformPathStr("wide."+wideColumnName(col)), pr.GetNumRows())
+					if err != nil {
+						b.Fatalf("ReadColumnByPath failed: %v", err)
+					}
+					for row, v := range values {
+						checkValue(b, col, row, v)
+					}
+					checkRows(b, col, len(values))
+				}
+
+				pr.ReadStop()
+			}()
+		}
+
+		report(b, bytesRead)
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		var bytesRead int64
+
+		// the file is read into structs that only have the projected
+		// columns.
+		typ := t.structType(cols)
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				v := reflect.New(typ)
+
+				r := parquet4.NewReader(f, parquet4.SchemaOf(v.Interface()))
+				row := 0
+				for ; ; row++ {
+					if err := r.Read(v.Interface()); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					for i, col := range cols {
+						switch field := v.Elem().Field(i); field.Kind() {
+						case reflect.Int32:
+							checkInt32(b, col, row, int32(field.Int()))
+						case reflect.Int64:
+							checkInt64(b, col, row, field.Int())
+						case reflect.Float64:
+							checkFloat64(b, col, row, field.Float())
+						case reflect.Bool:
+							checkBool(b, col, row, field.Bool())
+						case reflect.String:
+							checkString(b, col, row, field.String())
+						default:
+							b.Fatalf("unexpected field kind %s", field.Kind())
+						}
+					}
+				}
+				checkRows(b, cols[0], row)
+			}()
+		}
+
+		report(b, bytesRead)
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		var bytesRead int64
+
+		int32Values := make([]int32, 1024)
+		int64Values := make([]int64, 1024)
+		float64Values := make([]float64, 1024)
+		boolValues := make([]bool, 1024)
+		byteArrayValues := make([]parquet3.ByteArray, 1024)
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := file.NewParquetReader(f)
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				for _, col := range cols {
+					row := 0
+
+					for rg := 0; rg < r.NumRowGroups(); rg++ {
+						for cr := r.RowGroup(rg).Column(col); cr.HasNext(); {
+							var err error
+
+							switch cr := cr.(type) {
+							case *file.Int32ColumnChunkReader:
+								var n int
+								_, n, err = cr.ReadBatch(int64(len(int32Values)), int32Values, nil, nil)
+								for _, v := range int32Values[:n] {
+									checkInt32(b, col, row, v)
+									row++
+								}
+							case *file.Int64ColumnChunkReader:
+								var n int
+								_, n, err = cr.ReadBatch(int64(len(int64Values)), int64Values, nil, nil)
+								for _, v := range int64Values[:n] {
+									checkInt64(b, col, row, v)
+									row++
+								}
+							case *file.Float64ColumnChunkReader:
+								var n int
+								_, n, err = cr.ReadBatch(int64(len(float64Values)), float64Values, nil, nil)
+								for _, v := range float64Values[:n] {
+									checkFloat64(b, col, row, v)
+									row++
+								}
+							case *file.BooleanColumnChunkReader:
+								var n int
+								_, n, err = cr.ReadBatch(int64(len(boolValues)), boolValues, nil, nil)
+								for _, v := range boolValues[:n] {
+									checkBool(b, col, row, v)
+									row++
+								}
+							case *file.ByteArrayColumnChunkReader:
+								var n int
+								_, n, err = cr.ReadBatch(int64(len(byteArrayValues)), byteArrayValues, nil, nil)
+								for _, v := range byteArrayValues[:n] {
+									checkBytes(b, col, row, v)
+									row++
+								}
+							default:
+								b.Fatalf("unexpected column reader %T", cr)
+							}
+							if err != nil {
+								b.Fatalf("ReadBatch failed: %v", err)
+							}
+						}
+					}
+
+					checkRows(b, col, row)
+				}
+			}()
+		}
+
+		report(b, bytesRead)
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := file.NewParquetReader(f)
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+				if err != nil {
+					b.Fatalf("Creating pqarrow reader failed: %v", err)
+				}
+
+				// ReadRowGroups takes leaf column indices, and reads no
+				// row groups at all if none are given.
+				rowGroups := make([]int, r.NumRowGroups())
+				for rg := range rowGroups {
+					rowGroups[rg] = rg
+				}
+
+				tbl, err := fr.ReadRowGroups(context.Background(), cols, rowGroups)
+				if err != nil {
+					b.Fatalf("ReadRowGroups failed: %v", err)
+				}
+				defer tbl.Release()
+
+				for idx, col := range cols {
+					row := 0
+
+					for _, chunk := range tbl.Column(idx).Data().Chunks() {
+						for j := 0; j < chunk.Len(); j++ {
+							switch values := chunk.(type) {
+							case *array.Int32:
+								checkInt32(b, col, row, values.Value(j))
+							case *array.Int64:
+								checkInt64(b, col, row, values.Value(j))
+							case *array.Float64:
+								checkFloat64(b, col, row, values.Value(j))
+							case *array.Boolean:
+								checkBool(b, col, row, values.Value(j))
+							case *array.String:
+								checkString(b, col, row, values.Value(j))
+							default:
+								b.Fatalf("unexpected column array %T", chunk)
+							}
+							row++
+						}
+					}
+
+					checkRows(b, col, row)
+				}
+			}()
+		}
+
+		report(b, bytesRead)
+	})
+}
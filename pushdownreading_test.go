@@ -0,0 +1,486 @@
+package benchmark_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/metadata"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// The pushdown file has sorted ids spread across many row groups, so that
+// a range predicate on the id only matches a few of them.
+const (
+	pushdownRows         = 1000000
+	pushdownRowGroupSize = 10000
+)
+
+type pushdownRecord struct {
+	ID    int64   `parquet:"id"`
+	Value float64 `parquet:"value"`
+}
+
+// generatePushdownIDs returns n strictly increasing ids with random gaps.
+func generatePushdownIDs(n int) []int64 {
+	ids := make([]int64, n)
+	id := rand.Int63n(1000)
+	for i := range ids {
+		id += 1 + rand.Int63n(4)
+		ids[i] = id
+	}
+	return ids
+}
+
+// pushdownValue is the value that is stored alongside an id, so that
+// readers can verify that they didn't mix up rows.
+func pushdownValue(id int64) float64 {
+	return float64(id) / 4
+}
+
+// pushdownStatsMatch decodes the plain encoded int64 min and max
+// statistics of a column chunk and tells whether the range lo..hi can
+// match any of its values. Chunks without statistics always match.
+func pushdownStatsMatch(min, max []byte, lo, hi int64) bool {
+	if len(min) != 8 || len(max) != 8 {
+		return true
+	}
+	return int64(binary.LittleEndian.Uint64(max)) >= lo && int64(binary.LittleEndian.Uint64(min)) <= hi
+}
+
+// writePushdownFile writes the ids with apache arrow, one row group per
+// pushdownRowGroupSize rows. arrow ignores the statistics that parquet-go
+// writes because parquet-go doesn't write column orders, while all
+// readers here understand arrow's statistics.
+func writePushdownFile(filename string, ids []int64) error {
+	w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	sc, err := schema.NewGroupNode("pushdown", parquet3.Repetitions.Required, schema.FieldList{
+		schema.MustPrimitive(schema.NewPrimitiveNode("id", parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)),
+		schema.MustPrimitive(schema.NewPrimitiveNode("value", parquet3.Repetitions.Required, parquet3.Types.Double, 0, 0)),
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+
+	values := make([]float64, pushdownRowGroupSize)
+
+	for i := 0; i < len(ids); i += pushdownRowGroupSize {
+		rowGroupIDs := ids[i:]
+		if len(rowGroupIDs) > pushdownRowGroupSize {
+			rowGroupIDs = rowGroupIDs[:pushdownRowGroupSize]
+		}
+		for j, id := range rowGroupIDs {
+			values[j] = pushdownValue(id)
+		}
+
+		rg := pw.AppendRowGroup()
+
+		col, err := rg.NextColumn()
+		if err != nil {
+			return err
+		}
+		if _, err := col.(*file.Int64ColumnChunkWriter).WriteBatch(rowGroupIDs, nil, nil); err != nil {
+			return err
+		}
+		col.Close()
+
+		col, err = rg.NextColumn()
+		if err != nil {
+			return err
+		}
+		if _, err := col.(*file.Float64ColumnChunkWriter).WriteBatch(values[:len(rowGroupIDs)], nil, nil); err != nil {
+			return err
+		}
+		col.Close()
+
+		if err := rg.Close(); err != nil {
+			return err
+		}
+	}
+
+	return pw.Close()
+}
+
+func BenchmarkRowGroupSkipping(b *testing.B) {
+	ids := generatePushdownIDs(pushdownRows)
+
+	parquetFilename := "pushdownrd_testdata.parquet"
+
+	if err := writePushdownFile(parquetFilename, ids); err != nil {
+		b.Fatalf("Writing %s failed: %v", parquetFilename, err)
+	}
+
+	stat, err := os.Stat(parquetFilename)
+	if err != nil {
+		b.Fatalf("Stat failed: %v", err)
+	}
+
+	// the predicate selects about 1% of the rows, which straddle a row
+	// group boundary.
+	first, last := len(ids)*455/1000, len(ids)*465/1000
+
+	b.ResetTimer()
+
+	b.Run("stats", func(b *testing.B) {
+		benchmarkRowGroupSkipping(b, parquetFilename, stat.Size(), ids, first, last, true)
+	})
+
+	b.Run("scan", func(b *testing.B) {
+		benchmarkRowGroupSkipping(b, parquetFilename, stat.Size(), ids, first, last, false)
+	})
+}
+
+// benchmarkRowGroupSkipping finds the rows whose id lies between ids[first]
+// and ids[last]. With useStats, row groups whose min/max statistics rule
+// out a match are skipped, otherwise every row group is read.
+func benchmarkRowGroupSkipping(b *testing.B, parquetFilename string, fileSize int64, ids []int64, first, last int, useStats bool) {
+	lo, hi := ids[first], ids[last]
+
+	// match checks a row against the predicate and, if it matches,
+	// that it is the next expected row.
+	match := func(b *testing.B, next *int, id int64, v float64) {
+		if id < lo || id > hi {
+			return
+		}
+		if *next > last {
+			b.Fatalf("found more than the %d matching rows", last-first+1)
+		}
+		if id != ids[*next] {
+			b.Fatalf("matching row %d has id %d, expected %d", *next-first, id, ids[*next])
+		}
+		if v != pushdownValue(id) {
+			b.Fatalf("row with id %d has value %f, expected %f", id, v, pushdownValue(id))
+		}
+		*next++
+	}
+
+	checkMatches := func(b *testing.B, next int) {
+		if next != last+1 {
+			b.Fatalf("found %d matching rows, expected %d", next-first, last-first+1)
+		}
+	}
+
+	report := func(b *testing.B, bytesRead int64, skipped int) {
+		b.ReportMetric(float64(bytesRead)/float64(b.N), "bytes-read/op")
+		b.ReportMetric(float64(skipped)/float64(b.N), "rowgroups-skipped/op")
+		b.ReportMetric(float64(fileSize), "file-bytes")
+	}
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		var (
+			bytesRead int64
+			skipped   int
+		)
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				meta, err := goparquet.ReadFileMetaData(f, false)
+				if err != nil {
+					b.Fatalf("Reading file metadata failed: %v", err)
+				}
+
+				r, err := goparquet.NewFileReaderWithMetaData(f, meta)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				next := first
+				for rg, rowGroup := range meta.RowGroups {
+					if useStats {
+						stats := rowGroup.Columns[0].MetaData.Statistics
+						if stats != nil && !pushdownStatsMatch(stats.MinValue, stats.MaxValue, lo, hi) {
+							skipped++
+							continue
+						}
+					}
+
+					// SeekToRowGroup(i) loads the row group before i.
+					if err := r.SeekToRowGroup(rg + 1); err != nil {
+						b.Fatalf("SeekToRowGroup failed: %v", err)
+					}
+
+					for j := int64(0); j < rowGroup.NumRows; j++ {
+						values, err := r.NextRow()
+						if err != nil {
+							b.Fatalf("NextRow returned error: %v", err)
+						}
+						match(b, &next, values["id"].(int64), values["value"].(float64))
+					}
+				}
+				checkMatches(b, next)
+			}()
+		}
+
+		report(b, bytesRead, skipped)
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		var (
+			bytesRead int64
+			skipped   int
+		)
+
+		idPath := common.ReformPathStr("pushdown.id")
+		valuePath := common.ReformPathStr("pushdown.value")
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+				defer f.Close()
+
+				pr, err := reader.NewParquetColumnReader(f, 1)
+				if err != nil {
+					b.Fatalf("Creating parquet column reader failed: %v", err)
+				}
+
+				// rows of skipped row groups are skipped in the columns
+				// before the next row group that is read. xitongsys reads
+				// the pages of skipped rows instead of seeking past them.
+				var skipRows int64
+
+				next := first
+				for _, rowGroup := range pr.Footer.RowGroups {
+					if useStats {
+						stats := rowGroup.Columns[0].MetaData.Statistics
+						if stats != nil && !pushdownStatsMatch(stats.MinValue, stats.MaxValue, lo, hi) {
+							skipped++
+							skipRows += rowGroup.NumRows
+							continue
+						}
+					}
+
+					if skipRows > 0 {
+						for _, path := range []string{idPath, valuePath} {
+							if err := pr.SkipRowsByPath(path, skipRows); err != nil {
+								b.Fatalf("SkipRowsByPath failed: %v", err)
+							}
+						}
+						skipRows = 0
+					}
+
+					idValues, _, _, err := pr.ReadColumnByPath(idPath, rowGroup.NumRows)
+					if err != nil {
+						b.Fatalf("ReadColumnByPath failed: %v", err)
+					}
+					valueValues, _, _, err := pr.ReadColumnByPath(valuePath, rowGroup.NumRows)
+					if err != nil {
+						b.Fatalf("ReadColumnByPath failed: %v", err)
+					}
+					if len(idValues) != len(valueValues) {
+						b.Fatalf("read %d ids but %d values", len(idValues), len(valueValues))
+					}
+
+					for j := range idValues {
+						match(b, &next, idValues[j].(int64), valueValues[j].(float64))
+					}
+				}
+				checkMatches(b, next)
+
+				pr.ReadStop()
+			}()
+		}
+
+		report(b, bytesRead, skipped)
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		if useStats {
+			b.Skip("segmentio doesn't expose row group statistics")
+		}
+
+		var bytesRead int64
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r := parquet4.NewReader(f)
+				next := first
+				for {
+					var rec pushdownRecord
+					if err := r.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					match(b, &next, rec.ID, rec.Value)
+				}
+				checkMatches(b, next)
+			}()
+		}
+
+		report(b, bytesRead, 0)
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		var (
+			bytesRead int64
+			skipped   int
+		)
+
+		idValues := make([]int64, 1024)
+		valueValues := make([]float64, 1024)
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := file.NewParquetReader(f)
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				next := first
+				for rg := 0; rg < r.NumRowGroups(); rg++ {
+					if useStats {
+						cc, err := r.MetaData().RowGroup(rg).ColumnChunk(0)
+						if err != nil {
+							b.Fatalf("Getting column chunk metadata failed: %v", err)
+						}
+						stats, err := cc.Statistics()
+						if err != nil {
+							b.Fatalf("Getting statistics failed: %v", err)
+						}
+						if stats, ok := stats.(*metadata.Int64Statistics); ok && stats.HasMinMax() && (stats.Max() < lo || stats.Min() > hi) {
+							skipped++
+							continue
+						}
+					}
+
+					rgr := r.RowGroup(rg)
+					idReader := rgr.Column(0).(*file.Int64ColumnChunkReader)
+					valueReader := rgr.Column(1).(*file.Float64ColumnChunkReader)
+
+					for idReader.HasNext() {
+						_, n, err := idReader.ReadBatch(int64(len(idValues)), idValues, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						_, m, err := valueReader.ReadBatch(int64(n), valueValues, nil, nil)
+						if err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+						if m != n {
+							b.Fatalf("read %d ids but %d values", n, m)
+						}
+						for j := 0; j < n; j++ {
+							match(b, &next, idValues[j], valueValues[j])
+						}
+					}
+				}
+				checkMatches(b, next)
+			}()
+		}
+
+		report(b, bytesRead, skipped)
+	})
+
+	// pqarrow reads the row groups that are selected up front into a
+	// table.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		var (
+			bytesRead int64
+			skipped   int
+		)
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := openCountingFile(parquetFilename, &bytesRead)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := file.NewParquetReader(f)
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				var rowGroups []int
+				for rg := 0; rg < r.NumRowGroups(); rg++ {
+					if useStats {
+						cc, err := r.MetaData().RowGroup(rg).ColumnChunk(0)
+						if err != nil {
+							b.Fatalf("Getting column chunk metadata failed: %v", err)
+						}
+						stats, err := cc.Statistics()
+						if err != nil {
+							b.Fatalf("Getting statistics failed: %v", err)
+						}
+						if stats, ok := stats.(*metadata.Int64Statistics); ok && stats.HasMinMax() && (stats.Max() < lo || stats.Min() > hi) {
+							skipped++
+							continue
+						}
+					}
+					rowGroups = append(rowGroups, rg)
+				}
+
+				fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+				if err != nil {
+					b.Fatalf("Creating pqarrow reader failed: %v", err)
+				}
+
+				tbl, err := fr.ReadRowGroups(context.Background(), []int{0, 1}, rowGroups)
+				if err != nil {
+					b.Fatalf("ReadRowGroups failed: %v", err)
+				}
+				defer tbl.Release()
+
+				tr := array.NewTableReader(tbl, -1)
+				defer tr.Release()
+
+				next := first
+				for tr.Next() {
+					rec := tr.Record()
+					idValues := rec.Column(0).(*array.Int64).Int64Values()
+					valueValues := rec.Column(1).(*array.Float64).Float64Values()
+					for j := range idValues {
+						match(b, &next, idValues[j], valueValues[j])
+					}
+				}
+				checkMatches(b, next)
+			}()
+		}
+
+		report(b, bytesRead, skipped)
+	})
+}
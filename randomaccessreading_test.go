@@ -0,0 +1,392 @@
+package benchmark_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	goparquet "github.com/fraugster/parquet-go"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// randomAccessLookups is the number of different random rows that the
+// random access benchmarks cycle through.
+const randomAccessLookups = 1024
+
+func BenchmarkRandomAccessReading(b *testing.B) {
+	ids := generatePushdownIDs(pushdownRows)
+
+	parquetFilename := "randomaccessrd_testdata.parquet"
+
+	// the file has many row groups and, as segmentio writes page indexes,
+	// an offset index for each column chunk.
+	if err := writeSegmentioPageIndexFile(parquetFilename, ids, pushdownRowGroupSize); err != nil {
+		b.Fatalf("Writing %s failed: %v", parquetFilename, err)
+	}
+
+	b.ResetTimer()
+
+	b.Run("seek", func(b *testing.B) {
+		benchmarkRandomAccessReading(b, parquetFilename, ids, true)
+	})
+
+	b.Run("scan", func(b *testing.B) {
+		benchmarkRandomAccessReading(b, parquetFilename, ids, false)
+	})
+}
+
+// benchmarkRandomAccessReading reads single rows at random positions. With
+// seek, each library's mechanism to jump to a row group or row is used,
+// otherwise all rows before the requested one are read. The file is only
+// opened once where the library allows it, so that the lookups themselves
+// are measured.
+func benchmarkRandomAccessReading(b *testing.B, parquetFilename string, ids []int64, seek bool) {
+	positions := make([]int, randomAccessLookups)
+	for i := range positions {
+		positions[i] = rand.Intn(len(ids))
+	}
+
+	check := func(b *testing.B, pos int, id int64, v float64) {
+		if id != ids[pos] || v != pushdownValue(ids[pos]) {
+			b.Fatalf("row %d is %d/%f, expected %d/%f", pos, id, v, ids[pos], pushdownValue(ids[pos]))
+		}
+	}
+
+	report := func(b *testing.B, bytesRead int64) {
+		b.ReportMetric(float64(bytesRead)/float64(b.N), "bytes-read/op")
+	}
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		meta, err := goparquet.ReadFileMetaData(f, false)
+		if err != nil {
+			b.Fatalf("Reading file metadata failed: %v", err)
+		}
+
+		r, err := goparquet.NewFileReaderWithMetaData(f, meta)
+		if err != nil {
+			b.Fatalf("Reading parquet file failed: %v", err)
+		}
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			pos := positions[i%len(positions)]
+
+			rg, offset := 0, int64(pos)
+			if seek {
+				for offset >= meta.RowGroups[rg].NumRows {
+					offset -= meta.RowGroups[rg].NumRows
+					rg++
+				}
+			}
+
+			// SeekToRowGroup(i) loads the row group before i. When
+			// scanning, the reader continues with the following row
+			// groups by itself.
+			if err := r.SeekToRowGroup(rg + 1); err != nil {
+				b.Fatalf("SeekToRowGroup failed: %v", err)
+			}
+
+			var values map[string]interface{}
+			for j := int64(0); j <= offset; j++ {
+				if values, err = r.NextRow(); err != nil {
+					b.Fatalf("NextRow returned error: %v", err)
+				}
+			}
+			check(b, pos, values["id"].(int64), values["value"].(float64))
+		}
+
+		report(b, bytesRead)
+	})
+
+	// xitongsys can't rewind its column reader, so a new one is created for
+	// every lookup.
+	b.Run("xitongsys", func(b *testing.B) {
+		if seek {
+			b.Skip("xitongsys crashes when skipping rows in the v2 data pages that segmentio writes")
+		}
+
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Can't open file: %v", err)
+		}
+		defer f.Close()
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			pos := positions[i%len(positions)]
+
+			pr, err := reader.NewParquetColumnReader(f, 1)
+			if err != nil {
+				b.Fatalf("Creating parquet column reader failed: %v", err)
+			}
+
+			// segmentio names the root of the schema after the Go type.
+			root := pr.SchemaHandler.GetRootExName()
+
+			var row [2]interface{}
+			for c, name := range []string{"id", "value"} {
+				path := common.ReformPathStr(root + "." + name)
+
+				num := int64(pos + 1)
+				values, _, _, err := pr.ReadColumnByPath(path, num)
+				if err != nil {
+					b.Fatalf("ReadColumnByPath failed: %v", err)
+				}
+				if int64(len(values)) != num {
+					b.Fatalf("read %d values, expected %d", len(values), num)
+				}
+				row[c] = values[num-1]
+			}
+			check(b, pos, row[0].(int64), row[1].(float64))
+
+			pr.ReadStop()
+		}
+
+		report(b, bytesRead)
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil {
+			b.Fatalf("Stat failed: %v", err)
+		}
+
+		pf, err := parquet4.OpenFile(f, stat.Size())
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		var row parquet4.Row
+
+		for i := 0; i < b.N; i++ {
+			pos := positions[i%len(positions)]
+
+			rg, offset := 0, int64(pos)
+			for offset >= pf.RowGroup(rg).NumRows() {
+				if !seek {
+					// read all rows of the row groups before the one
+					// that contains the requested row.
+					rows := pf.RowGroup(rg).Rows()
+					for j := int64(0); j < pf.RowGroup(rg).NumRows(); j++ {
+						if row, err = rows.ReadRow(row[:0]); err != nil {
+							b.Fatalf("ReadRow failed: %v", err)
+						}
+					}
+				}
+				offset -= pf.RowGroup(rg).NumRows()
+				rg++
+			}
+
+			rows := pf.RowGroup(rg).Rows()
+			if seek {
+				// the offset index is used to jump to the page that
+				// contains the row.
+				if err := rows.SeekToRow(offset); err != nil {
+					b.Fatalf("SeekToRow failed: %v", err)
+				}
+				offset = 0
+			}
+
+			for j := int64(0); j <= offset; j++ {
+				if row, err = rows.ReadRow(row[:0]); err != nil {
+					b.Fatalf("ReadRow failed: %v", err)
+				}
+			}
+			check(b, pos, row[0].Int64(), row[1].Double())
+		}
+
+		report(b, bytesRead)
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		r, err := file.NewParquetReader(f)
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		idValues := make([]int64, 1024)
+		valueValues := make([]float64, 1024)
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			pos := positions[i%len(positions)]
+
+			rg, offset := 0, int64(pos)
+			for offset >= r.MetaData().RowGroup(rg).NumRows() {
+				offset -= r.MetaData().RowGroup(rg).NumRows()
+				rg++
+			}
+
+			if !seek {
+				// read all values of the row groups before the one that
+				// contains the requested row.
+				for j := 0; j < rg; j++ {
+					rgr := r.RowGroup(j)
+					for cr := rgr.Column(0).(*file.Int64ColumnChunkReader); cr.HasNext(); {
+						if _, _, err := cr.ReadBatch(int64(len(idValues)), idValues, nil, nil); err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+					}
+					for cr := rgr.Column(1).(*file.Float64ColumnChunkReader); cr.HasNext(); {
+						if _, _, err := cr.ReadBatch(int64(len(valueValues)), valueValues, nil, nil); err != nil {
+							b.Fatalf("ReadBatch failed: %v", err)
+						}
+					}
+				}
+			}
+
+			rgr := r.RowGroup(rg)
+			idReader := rgr.Column(0).(*file.Int64ColumnChunkReader)
+			valueReader := rgr.Column(1).(*file.Float64ColumnChunkReader)
+
+			if seek {
+				if _, err := idReader.Skip(offset); err != nil {
+					b.Fatalf("Skip failed: %v", err)
+				}
+				if _, err := valueReader.Skip(offset); err != nil {
+					b.Fatalf("Skip failed: %v", err)
+				}
+				offset = 0
+			}
+
+			var id int64
+			var v float64
+			for remaining := offset + 1; remaining > 0; {
+				batch := remaining
+				if batch > int64(len(idValues)) {
+					batch = int64(len(idValues))
+				}
+				_, n, err := idReader.ReadBatch(batch, idValues, nil, nil)
+				if err != nil {
+					b.Fatalf("ReadBatch failed: %v", err)
+				}
+				if _, _, err := valueReader.ReadBatch(int64(n), valueValues, nil, nil); err != nil {
+					b.Fatalf("ReadBatch failed: %v", err)
+				}
+				if n == 0 {
+					b.Fatalf("row %d is beyond the end of row group %d", pos, rg)
+				}
+				id, v = idValues[n-1], valueValues[n-1]
+				remaining -= int64(n)
+			}
+			check(b, pos, id, v)
+		}
+
+		report(b, bytesRead)
+	})
+
+	// pqarrow can't skip rows within a row group, so seeking reads the
+	// whole row group that contains the row, and scanning reads all row
+	// groups up to it.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		var bytesRead int64
+
+		f, err := openCountingFile(parquetFilename, &bytesRead)
+		if err != nil {
+			b.Fatalf("Opening file failed: %v", err)
+		}
+		defer f.Close()
+
+		r, err := file.NewParquetReader(f)
+		if err != nil {
+			b.Fatalf("Opening parquet file failed: %v", err)
+		}
+
+		fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+		if err != nil {
+			b.Fatalf("Creating pqarrow reader failed: %v", err)
+		}
+
+		cols := []int{0, 1}
+		rowGroups := make([]int, r.NumRowGroups())
+		for rg := range rowGroups {
+			rowGroups[rg] = rg
+		}
+
+		// valueAt returns the chunk that contains the row at offset, and
+		// the row's index in it.
+		valueAt := func(chunked *arrow.Chunked, offset int64) (arrow.Array, int) {
+			for _, chunk := range chunked.Chunks() {
+				if offset < int64(chunk.Len()) {
+					return chunk, int(offset)
+				}
+				offset -= int64(chunk.Len())
+			}
+			b.Fatalf("row %d is beyond the end of the column", offset)
+			return nil, 0
+		}
+
+		bytesRead = 0
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			pos := positions[i%len(positions)]
+
+			rg, offset := 0, int64(pos)
+			for offset >= r.MetaData().RowGroup(rg).NumRows() {
+				offset -= r.MetaData().RowGroup(rg).NumRows()
+				rg++
+			}
+
+			selected := rowGroups[rg : rg+1]
+			if !seek {
+				selected, offset = rowGroups[:rg+1], int64(pos)
+			}
+
+			tbl, err := fr.ReadRowGroups(context.Background(), cols, selected)
+			if err != nil {
+				b.Fatalf("ReadRowGroups failed: %v", err)
+			}
+
+			ids, j := valueAt(tbl.Column(0).Data(), offset)
+			values, k := valueAt(tbl.Column(1).Data(), offset)
+			check(b, pos, ids.(*array.Int64).Value(j), values.(*array.Float64).Value(k))
+
+			tbl.Release()
+		}
+
+		report(b, bytesRead)
+	})
+}
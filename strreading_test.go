@@ -0,0 +1,407 @@
+package benchmark_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+var strEncodings = []struct {
+	name    string
+	useDict bool
+}{
+	{name: "plain", useDict: false},
+	{name: "dict", useDict: true},
+}
+
+func BenchmarkStringReading(b *testing.B) {
+	words := loadWords(b)
+
+	for _, enc := range strEncodings {
+		enc := enc
+
+		b.Run(enc.name, func(b *testing.B) {
+			benchmarkStringReading(b, words, enc.useDict, "strrd_"+enc.name+"_")
+		})
+	}
+}
+
+// benchmarkStringReading compares every decoded word against the original
+// list and reports the heap allocations per string next to the usual
+// allocs/op.
+func benchmarkStringReading(b *testing.B, words []string, useDict bool, prefix string) {
+	parquetFilename := prefix + "testdata.parquet"
+
+	writeWordsFile(b, parquetFilename, words, useDict)
+
+	checkString := func(b *testing.B, row int, v string) {
+		if row >= len(words) {
+			b.Fatalf("read more than the %d rows that were written", len(words))
+		}
+		if v != words[row] {
+			b.Fatalf("row %d is %q, expected %q", row, v, words[row])
+		}
+	}
+
+	checkBytes := func(b *testing.B, row int, v []byte) {
+		if row >= len(words) {
+			b.Fatalf("read more than the %d rows that were written", len(words))
+		}
+		if string(v) != words[row] {
+			b.Fatalf("row %d is %q, expected %q", row, v, words[row])
+		}
+	}
+
+	b.ResetTimer()
+
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		defer reportAllocsPerString(b, len(words))()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				for row := 0; ; row++ {
+					values, err := r.NextRow()
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("NextRow returned error: %v", err)
+					}
+					word, ok := values["word"].([]byte)
+					if !ok {
+						b.Fatalf("row %d: unexpected word %T", row, values["word"])
+					}
+					checkBytes(b, row, word)
+				}
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_reflection", func(b *testing.B) {
+		type record struct {
+			Word string `parquet:"word"`
+		}
+
+		defer reportAllocsPerString(b, len(words))()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var rec record
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					checkString(b, row, rec.Word)
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("parquet_floor_unmarshal", func(b *testing.B) {
+		defer reportAllocsPerString(b, len(words))()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := floor.NewFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+
+				for row := 0; r.Next(); row++ {
+					var rec myStrRecord
+					if err := r.Scan(&rec); err != nil {
+						b.Fatalf("Scan failed: %v", err)
+					}
+					checkBytes(b, row, rec.Word)
+				}
+
+				r.Close()
+			}()
+		}
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		type record struct {
+			Word string `parquet:"name=word, type=BYTE_ARRAY, convertedtype=UTF8"`
+		}
+
+		defer reportAllocsPerString(b, len(words))()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+
+				pr, err := reader.NewParquetReader(fr, new(record), 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				num := int(pr.GetNumRows())
+				row := 0
+
+				for num > 0 {
+					sliceSize := 100
+					if num < sliceSize {
+						sliceSize = num
+					}
+					rec := make([]record, sliceSize)
+					if err := pr.Read(&rec); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("Read failed: %v", err)
+					}
+					for _, r := range rec {
+						checkString(b, row, r.Word)
+						row++
+					}
+
+					num -= sliceSize
+				}
+
+				pr.ReadStop()
+				fr.Close()
+			}()
+		}
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		defer reportAllocsPerString(b, len(words))()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+				r := parquet4.NewReader(f)
+				var row parquet4.Row
+				for n := 0; ; n++ {
+					row, err = r.ReadRow(row[:0])
+					if err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						b.Fatalf("ReadRow failed: %v", err)
+					}
+					checkBytes(b, n, row[0].ByteArray())
+				}
+			}()
+		}
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				values := make([]parquet3.ByteArray, batchSize)
+
+				defer reportAllocsPerString(b, len(words))()
+
+				for i := 0; i < b.N; i++ {
+					func() {
+						r, err := file.OpenParquetFile(parquetFilename, false)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+						defer r.Close()
+
+						row := 0
+
+						for rg := 0; rg < r.NumRowGroups(); rg++ {
+							col, ok := r.RowGroup(rg).Column(0).(*file.ByteArrayColumnChunkReader)
+							if !ok {
+								b.Fatalf("couldn't assert word column which is %T", r.RowGroup(rg).Column(0))
+							}
+
+							for col.HasNext() {
+								_, n, err := col.ReadBatch(int64(len(values)), values, nil, nil)
+								if err != nil {
+									b.Fatalf("ReadBatch failed: %v", err)
+								}
+								for _, v := range values[:n] {
+									checkBytes(b, row, v)
+									row++
+								}
+							}
+						}
+					}()
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow_records", func(b *testing.B) {
+		for _, batchSize := range arrowReadBatchSizes {
+			b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+				defer reportAllocsPerString(b, len(words))()
+
+				for i := 0; i < b.N; i++ {
+					row := 0
+					if err := readPqarrowRecords(parquetFilename, batchSize, func(rec arrow.Record) error {
+						values := rec.Column(0).(*array.String)
+						for j := 0; j < values.Len(); j++ {
+							checkString(b, row, values.Value(j))
+							row++
+						}
+						return nil
+					}); err != nil {
+						b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+					}
+					if row != len(words) {
+						b.Fatalf("read %d rows, expected %d", row, len(words))
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		defer reportAllocsPerString(b, len(words))()
+
+		for i := 0; i < b.N; i++ {
+			func() {
+				tbl, err := readPqarrowTable(parquetFilename)
+				if err != nil {
+					b.Fatalf("Reading %s failed: %v", parquetFilename, err)
+				}
+				defer tbl.Release()
+
+				row := 0
+				for _, chunk := range tbl.Column(0).Data().Chunks() {
+					values := chunk.(*array.String)
+					for j := 0; j < values.Len(); j++ {
+						checkString(b, row, values.Value(j))
+						row++
+					}
+				}
+				if row != len(words) {
+					b.Fatalf("read %d rows, expected %d", row, len(words))
+				}
+			}()
+		}
+	})
+}
+
+// loadWords returns the contents of testdata/words.txt, one word per line.
+func loadWords(b *testing.B) []string {
+	f, err := os.Open("testdata/words.txt")
+	if err != nil {
+		b.Fatalf("Opening words.txt failed: %v", err)
+	}
+	defer f.Close()
+
+	var words []string
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		words = append(words, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		b.Fatalf("Reading words.txt failed: %v", err)
+	}
+
+	return words
+}
+
+// writeWordsFile writes words into a single string column, either PLAIN
+// encoded or dictionary encoded.
+func writeWordsFile(b *testing.B, parquetFilename string, words []string, useDict bool) {
+	w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+	}
+	defer w.Close()
+
+	fw := goparquet.NewFileWriter(w, goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+	byteArrayStore, err := goparquet.NewByteArrayStore(
+		parquet.Encoding_PLAIN,
+		useDict,
+		&goparquet.ColumnParameters{
+			LogicalType: &parquet.LogicalType{
+				STRING: parquet.NewStringType(),
+			},
+			ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+		},
+	)
+	if err != nil {
+		b.Fatalf("NewByteArrayStore failed: %v", err)
+	}
+	if err := fw.AddColumn("word", goparquet.NewDataColumn(byteArrayStore, parquet.FieldRepetitionType_REQUIRED)); err != nil {
+		b.Fatalf("AddColumn failed: %v", err)
+	}
+
+	for _, word := range words {
+		if err := fw.AddData(map[string]interface{}{"word": []byte(word)}); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+}
+
+// reportAllocsPerString snapshots the allocation counter and returns a
+// function that reports the heap allocations per decoded string since the
+// snapshot. It's meant to be deferred at the start of a sub-benchmark.
+func reportAllocsPerString(b *testing.B, numStrings int) func() {
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	return func() {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.Mallocs-before.Mallocs)/float64(b.N*numStrings), "allocs/string")
+	}
+}
+
+type myStrRecord struct {
+	Word []byte
+}
+
+func (r *myStrRecord) UnmarshalParquet(obj interfaces.UnmarshalObject) error {
+	word, err := obj.GetField("word").ByteArray()
+	if err != nil {
+		return err
+	}
+	r.Word = word
+	return nil
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
 	parquet3 "github.com/apache/arrow/go/v8/parquet"
 	"github.com/apache/arrow/go/v8/parquet/compress"
 	"github.com/apache/arrow/go/v8/parquet/file"
@@ -235,42 +238,74 @@ func BenchmarkStringWriting(b *testing.B) {
 	})
 
 	b.Run("apache_arrow_parquet", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			func() {
-				filename := prefix + "apache_arrow_parquet.parquet"
-				w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-				if err != nil {
-					b.Fatalf("Opening file failed: %v", err)
-				}
+		values := make([]parquet3.ByteArray, len(words))
+		for i, word := range words {
+			values[i] = parquet3.ByteArray(word)
+		}
 
-				sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
-					schema.MustPrimitive(schema.NewPrimitiveNodeLogical("word", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
-				}, 0)
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					func() {
+						filename := prefix + "apache_arrow_parquet.parquet"
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
 
-				pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
-				defer pw.Close()
+						sc, err := schema.NewGroupNode("test", parquet3.Repetitions.Required, schema.FieldList{
+							schema.MustPrimitive(schema.NewPrimitiveNodeLogical("word", parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)),
+						}, 0)
 
-				rg := pw.AppendRowGroup()
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
 
-				col, err := rg.NextColumn()
-				if err != nil {
-					b.Fatalf("NextColumn failed: %v", err)
-				}
+						rg := pw.AppendRowGroup()
 
-				wordCol, ok := col.(*file.ByteArrayColumnChunkWriter)
-				if !ok {
-					b.Fatalf("couldn't assert third column which is %T", col)
-				}
+						col, err := rg.NextColumn()
+						if err != nil {
+							b.Fatalf("NextColumn failed: %v", err)
+						}
 
-				for _, word := range words {
-					if _, err := wordCol.WriteBatch([]parquet3.ByteArray{[]byte(word)}, nil, nil); err != nil {
-						b.Fatalf("WriteBatch failed: %v", err)
-					}
+						wordCol, ok := col.(*file.ByteArrayColumnChunkWriter)
+						if !ok {
+							b.Fatalf("couldn't assert third column which is %T", col)
+						}
+
+						if err := writeBatches(len(values), batchSize, func(start, end int) error {
+							_, err := wordCol.WriteBatch(values[start:end], nil, nil)
+							return err
+						}); err != nil {
+							b.Fatalf("WriteBatch failed: %v", err)
+						}
+
+						wordCol.Close()
+
+						defer rg.Close()
+					}()
 				}
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
 
-				wordCol.Close()
+		sc := arrow.NewSchema([]arrow.Field{{Name: "word", Type: arrow.BinaryTypes.String}}, nil)
 
-				defer rg.Close()
+		for n := 0; n < b.N; n++ {
+			func() {
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				bld.Field(0).(*array.StringBuilder).AppendValues(words, nil)
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := writePqarrowRecord(filename, rec); err != nil {
+					b.Fatalf("Writing %s failed: %v", filename, err)
+				}
 			}()
 		}
 	})
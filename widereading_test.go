@@ -0,0 +1,186 @@
+package benchmark_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func BenchmarkWideSchemaOpening(b *testing.B) {
+	for _, width := range wideWidths {
+		width := width
+
+		b.Run(fmt.Sprint(width), func(b *testing.B) {
+			t := generateWideTable(width, wideRows)
+			b.ResetTimer()
+
+			benchmarkWideSchemaOpening(b, t, fmt.Sprintf("widerd_%d_", width))
+		})
+	}
+}
+
+// benchmarkWideSchemaOpening measures how long it takes to open a file
+// and get at its schema and row count, which for wide schemas is mostly
+// spent decoding the footer and setting up per-column state.
+func benchmarkWideSchemaOpening(b *testing.B, t wideTable, prefix string) {
+	schemaDef, err := parquetschema.ParseSchemaDefinition(t.schemaDefinition())
+	if err != nil {
+		b.Fatalf("Parsing schema definition failed: %v", err)
+	}
+
+	parquetFilename := prefix + "testdata.parquet"
+
+	fw, err := floor.NewFileWriter(parquetFilename,
+		goparquet.WithSchemaDefinition(schemaDef),
+		goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+	)
+	if err != nil {
+		b.Fatalf("Opening parquet file for writing failed: %v", err)
+	}
+
+	for i := range t.rows {
+		if err = fw.Write(wideRecord{table: &t, row: i}); err != nil {
+			b.Fatalf("Write error: %v", err)
+		}
+	}
+
+	if err := fw.Close(); err != nil {
+		b.Fatalf("Closing parquet writer failed: %v", err)
+	}
+
+	check := func(b *testing.B, numRows int64, numColumns int) {
+		if numRows != int64(len(t.rows)) {
+			b.Fatalf("file has %d rows, expected %d", numRows, len(t.rows))
+		}
+		if numColumns != t.numColumns {
+			b.Fatalf("file has %d columns, expected %d", numColumns, t.numColumns)
+		}
+	}
+
+	footerSize, err := parquetFooterSize(parquetFilename)
+	if err != nil {
+		b.Fatalf("Reading footer size failed: %v", err)
+	}
+
+	b.ResetTimer()
+
+	// floor opens files through the low-level reader, so it isn't
+	// benchmarked separately.
+	b.Run("parquet_lowlevel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				r, err := goparquet.NewFileReader(f)
+				if err != nil {
+					b.Fatalf("Reading parquet file failed: %v", err)
+				}
+
+				check(b, r.NumRows(), len(r.Columns()))
+			}()
+		}
+		b.ReportMetric(float64(footerSize), "footer-bytes")
+	})
+
+	b.Run("xitongsys", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				fr, err := local.NewLocalFileReader(parquetFilename)
+				if err != nil {
+					b.Fatalf("Can't open file: %v", err)
+				}
+				defer fr.Close()
+
+				pr, err := reader.NewParquetReader(fr, nil, 1)
+				if err != nil {
+					b.Fatalf("Creating parquet reader failed: %v", err)
+				}
+
+				check(b, pr.GetNumRows(), len(pr.SchemaHandler.ValueColumns))
+				pr.ReadStop()
+			}()
+		}
+		b.ReportMetric(float64(footerSize), "footer-bytes")
+	})
+
+	b.Run("segmentio", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				f, err := os.Open(parquetFilename)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer f.Close()
+
+				stat, err := f.Stat()
+				if err != nil {
+					b.Fatalf("Stat failed: %v", err)
+				}
+
+				pf, err := parquet4.OpenFile(f, stat.Size())
+				if err != nil {
+					b.Fatalf("Opening parquet file failed: %v", err)
+				}
+
+				check(b, pf.NumRows(), len(pf.Schema().Fields()))
+			}()
+		}
+		b.ReportMetric(float64(footerSize), "footer-bytes")
+	})
+
+	b.Run("apache_arrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := file.OpenParquetFile(parquetFilename, false)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer r.Close()
+
+				check(b, r.NumRows(), r.MetaData().Schema.NumColumns())
+			}()
+		}
+		b.ReportMetric(float64(footerSize), "footer-bytes")
+	})
+
+	// pqarrow also converts the parquet schema into an arrow schema.
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			func() {
+				r, err := file.OpenParquetFile(parquetFilename, false)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer r.Close()
+
+				fr, err := pqarrow.NewFileReader(r, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+				if err != nil {
+					b.Fatalf("Creating pqarrow reader failed: %v", err)
+				}
+
+				sc, err := fr.Schema()
+				if err != nil {
+					b.Fatalf("Converting schema failed: %v", err)
+				}
+
+				check(b, r.NumRows(), len(sc.Fields()))
+			}()
+		}
+		b.ReportMetric(float64(footerSize), "footer-bytes")
+	})
+}
@@ -0,0 +1,760 @@
+package benchmark_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	parquet3 "github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/compress"
+	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/apache/arrow/go/v8/parquet/schema"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/floor/interfaces"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	parquet4 "github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/snappy"
+	parquet2 "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// wideWidths are the numbers of columns of the wide schemas. Each of them
+// is written with only wideRows rows, so the per-column costs of creating
+// writers, flushing column chunks and encoding the footer dominate.
+var wideWidths = []int{100, 1000, 5000}
+
+const wideRows = 100
+
+// The types the columns of a wide schema cycle through.
+const (
+	wideInt32 = iota
+	wideInt64
+	wideDouble
+	wideBoolean
+	wideString
+	numWideTypes
+)
+
+func wideColumnType(col int) int {
+	return col % numWideTypes
+}
+
+func wideColumnName(col int) string {
+	return fmt.Sprintf("c%04d", col)
+}
+
+// wideTable holds the rows of a wide schema. The values are int32, int64,
+// float64, bool or string, depending on the column.
+type wideTable struct {
+	numColumns int
+	rows       [][]interface{}
+}
+
+func generateWideTable(numColumns, numRows int) wideTable {
+	t := wideTable{
+		numColumns: numColumns,
+		rows:       make([][]interface{}, numRows),
+	}
+	for i := range t.rows {
+		row := make([]interface{}, numColumns)
+		for col := range row {
+			switch wideColumnType(col) {
+			case wideInt32:
+				row[col] = rand.Int31()
+			case wideInt64:
+				row[col] = rand.Int63()
+			case wideDouble:
+				row[col] = rand.NormFloat64()
+			case wideBoolean:
+				row[col] = rand.Intn(2) == 0
+			case wideString:
+				row[col] = fmt.Sprintf("value %d", rand.Intn(1000))
+			}
+		}
+		t.rows[i] = row
+	}
+	return t
+}
+
+func (t wideTable) schemaDefinition() string {
+	var sb strings.Builder
+	sb.WriteString("message wide {\n")
+	for col := 0; col < t.numColumns; col++ {
+		switch wideColumnType(col) {
+		case wideInt32:
+			fmt.Fprintf(&sb, "\trequired int32 %s;\n", wideColumnName(col))
+		case wideInt64:
+			fmt.Fprintf(&sb, "\trequired int64 %s;\n", wideColumnName(col))
+		case wideDouble:
+			fmt.Fprintf(&sb, "\trequired double %s;\n", wideColumnName(col))
+		case wideBoolean:
+			fmt.Fprintf(&sb, "\trequired boolean %s;\n", wideColumnName(col))
+		case wideString:
+			fmt.Fprintf(&sb, "\trequired binary %s (STRING);\n", wideColumnName(col))
+		}
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// xitongsysMetadata returns the schema in the notation of xitongsys' CSV
+// writer, which takes rows as slices of values instead of structs.
+func (t wideTable) xitongsysMetadata() []string {
+	md := make([]string, t.numColumns)
+	for col := range md {
+		switch wideColumnType(col) {
+		case wideInt32:
+			md[col] = "name=" + wideColumnName(col) + ", type=INT32"
+		case wideInt64:
+			md[col] = "name=" + wideColumnName(col) + ", type=INT64"
+		case wideDouble:
+			md[col] = "name=" + wideColumnName(col) + ", type=DOUBLE"
+		case wideBoolean:
+			md[col] = "name=" + wideColumnName(col) + ", type=BOOLEAN"
+		case wideString:
+			md[col] = "name=" + wideColumnName(col) + ", type=BYTE_ARRAY, convertedtype=UTF8"
+		}
+	}
+	return md
+}
+
+func (t wideTable) arrowSchema() (*schema.GroupNode, error) {
+	fields := make(schema.FieldList, t.numColumns)
+	for col := range fields {
+		var (
+			node schema.Node
+			err  error
+		)
+		switch wideColumnType(col) {
+		case wideInt32:
+			node, err = schema.NewPrimitiveNode(wideColumnName(col), parquet3.Repetitions.Required, parquet3.Types.Int32, 0, 0)
+		case wideInt64:
+			node, err = schema.NewPrimitiveNode(wideColumnName(col), parquet3.Repetitions.Required, parquet3.Types.Int64, 0, 0)
+		case wideDouble:
+			node, err = schema.NewPrimitiveNode(wideColumnName(col), parquet3.Repetitions.Required, parquet3.Types.Double, 0, 0)
+		case wideBoolean:
+			node, err = schema.NewPrimitiveNode(wideColumnName(col), parquet3.Repetitions.Required, parquet3.Types.Boolean, 0, 0)
+		case wideString:
+			node, err = schema.NewPrimitiveNodeLogical(wideColumnName(col), parquet3.Repetitions.Required, &schema.StringLogicalType{}, parquet3.Types.ByteArray, 0, 0)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields[col] = node
+	}
+	return schema.NewGroupNode("wide", parquet3.Repetitions.Required, fields, 0)
+}
+
+// pqarrowSchema returns the arrow schema that pqarrow maps to the wide
+// schema.
+func (t wideTable) pqarrowSchema() *arrow.Schema {
+	fields := make([]arrow.Field, t.numColumns)
+	for col := range fields {
+		fields[col].Name = wideColumnName(col)
+		switch wideColumnType(col) {
+		case wideInt32:
+			fields[col].Type = arrow.PrimitiveTypes.Int32
+		case wideInt64:
+			fields[col].Type = arrow.PrimitiveTypes.Int64
+		case wideDouble:
+			fields[col].Type = arrow.PrimitiveTypes.Float64
+		case wideBoolean:
+			fields[col].Type = arrow.FixedWidthTypes.Boolean
+		case wideString:
+			fields[col].Type = arrow.BinaryTypes.String
+		}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// structType returns a struct type with one field for each of the given
+// columns. The type is created at runtime, and its tags are understood by
+// both floor and segmentio.
+func (t wideTable) structType(cols []int) reflect.Type {
+	fields := make([]reflect.StructField, len(cols))
+	for i, col := range cols {
+		fields[i] = reflect.StructField{
+			Name: strings.ToUpper(wideColumnName(col)),
+			Type: reflect.TypeOf(t.rows[0][col]),
+			Tag:  reflect.StructTag(`parquet:"` + wideColumnName(col) + `"`),
+		}
+	}
+	return reflect.StructOf(fields)
+}
+
+// structs returns the rows as pointers to structs of structType with all
+// columns.
+func (t wideTable) structs() []interface{} {
+	cols := make([]int, t.numColumns)
+	for col := range cols {
+		cols[col] = col
+	}
+	typ := t.structType(cols)
+
+	structs := make([]interface{}, len(t.rows))
+	for i, row := range t.rows {
+		v := reflect.New(typ)
+		for col, x := range row {
+			v.Elem().Field(col).Set(reflect.ValueOf(x))
+		}
+		structs[i] = v.Interface()
+	}
+	return structs
+}
+
+// column returns the values of a column as a typed slice: []int32,
+// []int64, []float64, []bool or []string, depending on the column.
+func (t wideTable) column(col int) interface{} {
+	switch wideColumnType(col) {
+	case wideInt32:
+		values := make([]int32, len(t.rows))
+		for i, row := range t.rows {
+			values[i] = row[col].(int32)
+		}
+		return values
+	case wideInt64:
+		values := make([]int64, len(t.rows))
+		for i, row := range t.rows {
+			values[i] = row[col].(int64)
+		}
+		return values
+	case wideDouble:
+		values := make([]float64, len(t.rows))
+		for i, row := range t.rows {
+			values[i] = row[col].(float64)
+		}
+		return values
+	case wideBoolean:
+		values := make([]bool, len(t.rows))
+		for i, row := range t.rows {
+			values[i] = row[col].(bool)
+		}
+		return values
+	default:
+		values := make([]string, len(t.rows))
+		for i, row := range t.rows {
+			values[i] = row[col].(string)
+		}
+		return values
+	}
+}
+
+// object converts a row into the map representation used by parquet-go's
+// low-level API.
+func (t wideTable) object(row int) map[string]interface{} {
+	obj := make(map[string]interface{}, t.numColumns)
+	for col, v := range t.rows[row] {
+		if s, ok := v.(string); ok {
+			obj[wideColumnName(col)] = []byte(s)
+		} else {
+			obj[wideColumnName(col)] = v
+		}
+	}
+	return obj
+}
+
+// wideRecord brings its own marshalling code for a row of a wide table.
+type wideRecord struct {
+	table *wideTable
+	row   int
+}
+
+func (r wideRecord) MarshalParquet(obj interfaces.MarshalObject) error {
+	for col, v := range r.table.rows[r.row] {
+		field := obj.AddField(wideColumnName(col))
+		switch v := v.(type) {
+		case int32:
+			field.SetInt32(v)
+		case int64:
+			field.SetInt64(v)
+		case float64:
+			field.SetFloat64(v)
+		case bool:
+			field.SetBool(v)
+		case string:
+			field.SetByteArray([]byte(v))
+		}
+	}
+	return nil
+}
+
+// verifyWideFile reads back a file and checks that every value of every
+// column survived the round trip.
+func verifyWideFile(b *testing.B, filename string, t wideTable) {
+	f, err := os.Open(filename)
+	if err != nil {
+		b.Fatalf("Opening file failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		b.Fatalf("Reading parquet file failed: %v", err)
+	}
+
+	if n := len(r.Columns()); n != t.numColumns {
+		b.Fatalf("%s: file has %d columns, expected %d", filename, n, t.numColumns)
+	}
+
+	for i := 0; ; i++ {
+		row, err := r.NextRow()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if i != len(t.rows) {
+					b.Fatalf("%s: read %d rows, expected %d", filename, i, len(t.rows))
+				}
+				break
+			}
+			b.Fatalf("NextRow returned error: %v", err)
+		}
+		if i >= len(t.rows) {
+			b.Fatalf("%s: read more than the %d rows that were written", filename, len(t.rows))
+		}
+
+		for col, expected := range t.rows[i] {
+			v := row[wideColumnName(col)]
+			if s, ok := v.([]byte); ok {
+				v = string(s)
+			}
+			if v != expected {
+				b.Fatalf("%s: row %d column %s is %v, expected %v", filename, i, wideColumnName(col), v, expected)
+			}
+		}
+	}
+}
+
+// parquetFooterSize returns the size of the thrift-encoded file metadata,
+// which is stored right before the trailing magic bytes.
+func parquetFooterSize(filename string) (int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(-8, io.SeekEnd); err != nil {
+		return 0, err
+	}
+
+	var tail [8]byte
+	if _, err := io.ReadFull(f, tail[:]); err != nil {
+		return 0, err
+	}
+	if string(tail[4:]) != "PAR1" {
+		return 0, fmt.Errorf("%s doesn't end in PAR1", filename)
+	}
+
+	return int(binary.LittleEndian.Uint32(tail[:4])), nil
+}
+
+// reportWideWriting reports the average time that was spent creating the
+// writer, and the size of the footer of the written file.
+func reportWideWriting(b *testing.B, filename string, created time.Duration) {
+	footerSize, err := parquetFooterSize(filename)
+	if err != nil {
+		b.Fatalf("Reading footer size failed: %v", err)
+	}
+
+	b.ReportMetric(float64(created.Nanoseconds())/float64(b.N), "create-ns/op")
+	b.ReportMetric(float64(footerSize), "footer-bytes")
+}
+
+func BenchmarkWideSchemaWriting(b *testing.B) {
+	for _, width := range wideWidths {
+		width := width
+
+		b.Run(fmt.Sprint(width), func(b *testing.B) {
+			t := generateWideTable(width, wideRows)
+			b.ResetTimer()
+
+			benchmarkWideSchemaWriting(b, t, fmt.Sprintf("widewr_%d_", width))
+		})
+	}
+}
+
+func benchmarkWideSchemaWriting(b *testing.B, t wideTable, prefix string) {
+	structs := t.structs()
+
+	b.Run("parquet_go_floor_reflection", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_reflection.parquet"
+
+		var created time.Duration
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				start := time.Now()
+
+				schemaDef, err := parquetschema.ParseSchemaDefinition(t.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				created += time.Since(start)
+
+				for _, s := range structs {
+					if err = fw.Write(s); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyWideFile(b, parquetFilename, t)
+		reportWideWriting(b, parquetFilename, created)
+	})
+
+	b.Run("parquet_go_floor_marshalling", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_floor_marshalling.parquet"
+
+		var created time.Duration
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				start := time.Now()
+
+				schemaDef, err := parquetschema.ParseSchemaDefinition(t.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				fw, err := floor.NewFileWriter(parquetFilename,
+					goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+				)
+				if err != nil {
+					b.Fatalf("Opening parquet file for writing failed: %v", err)
+				}
+
+				created += time.Since(start)
+
+				for i := range t.rows {
+					if err = fw.Write(wideRecord{table: &t, row: i}); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyWideFile(b, parquetFilename, t)
+		reportWideWriting(b, parquetFilename, created)
+	})
+
+	b.Run("parquet_go_lowlevel", func(b *testing.B) {
+		parquetFilename := prefix + "parquet_go_lowlevel.parquet"
+
+		var created time.Duration
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				start := time.Now()
+
+				schemaDef, err := parquetschema.ParseSchemaDefinition(t.schemaDefinition())
+				if err != nil {
+					b.Fatalf("Parsing schema definition failed: %v", err)
+				}
+
+				w, err := os.OpenFile(parquetFilename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening %s failed: %v", parquetFilename, err)
+				}
+
+				defer w.Close()
+
+				fw := goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef),
+					goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY))
+
+				created += time.Since(start)
+
+				for i := range t.rows {
+					if err = fw.AddData(t.object(i)); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyWideFile(b, parquetFilename, t)
+		reportWideWriting(b, parquetFilename, created)
+	})
+
+	b.Run("xitongsys_parquet_go", func(b *testing.B) {
+		filename := prefix + "xitongsys_parquet_go.parquet"
+
+		var created time.Duration
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				start := time.Now()
+
+				w, err := os.Create(filename)
+				if err != nil {
+					b.Fatalf("Can't create local file: %v", err)
+				}
+
+				//write
+				pw, err := writer.NewCSVWriterFromWriter(t.xitongsysMetadata(), w, 4)
+				if err != nil {
+					b.Fatalf("Can't create parquet writer: %v", err)
+				}
+
+				pw.CompressionType = parquet2.CompressionCodec_SNAPPY
+
+				created += time.Since(start)
+
+				for _, row := range t.rows {
+					if err = pw.Write(row); err != nil {
+						b.Fatalf("Write error: %v", err)
+					}
+				}
+				if err = pw.WriteStop(); err != nil {
+					b.Fatalf("WriteStop error: %v", err)
+				}
+				w.Close()
+			}()
+		}
+
+		b.StopTimer()
+		verifyWideFile(b, filename, t)
+		reportWideWriting(b, filename, created)
+	})
+
+	b.Run("apache_arrow_parquet", func(b *testing.B) {
+		// the columns are converted to the types the column writers take
+		// once, so that the conversion isn't timed.
+		columns := make([]interface{}, t.numColumns)
+		for c := range columns {
+			columns[c] = t.column(c)
+			if strs, ok := columns[c].([]string); ok {
+				values := make([]parquet3.ByteArray, len(strs))
+				for i, s := range strs {
+					values[i] = parquet3.ByteArray(s)
+				}
+				columns[c] = values
+			}
+		}
+
+		for _, batchSize := range arrowWriteBatchSizes {
+			b.Run(arrowBatchName(batchSize), func(b *testing.B) {
+				filename := prefix + "apache_arrow_parquet.parquet"
+
+				var created time.Duration
+
+				for n := 0; n < b.N; n++ {
+					func() {
+						start := time.Now()
+
+						w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+						if err != nil {
+							b.Fatalf("Opening file failed: %v", err)
+						}
+
+						sc, err := t.arrowSchema()
+						if err != nil {
+							b.Fatalf("Creating schema failed: %v", err)
+						}
+
+						pw := file.NewParquetWriter(w, sc, file.WithWriterProps(parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy))))
+						defer pw.Close()
+
+						created += time.Since(start)
+
+						rg := pw.AppendRowGroup()
+						defer rg.Close()
+
+						for c := 0; c < t.numColumns; c++ {
+							col, err := rg.NextColumn()
+							if err != nil {
+								b.Fatalf("NextColumn failed: %v", err)
+							}
+
+							switch col := col.(type) {
+							case *file.Int32ColumnChunkWriter:
+								values := columns[c].([]int32)
+								err = writeBatches(len(values), batchSize, func(start, end int) error {
+									_, err := col.WriteBatch(values[start:end], nil, nil)
+									return err
+								})
+							case *file.Int64ColumnChunkWriter:
+								values := columns[c].([]int64)
+								err = writeBatches(len(values), batchSize, func(start, end int) error {
+									_, err := col.WriteBatch(values[start:end], nil, nil)
+									return err
+								})
+							case *file.Float64ColumnChunkWriter:
+								values := columns[c].([]float64)
+								err = writeBatches(len(values), batchSize, func(start, end int) error {
+									_, err := col.WriteBatch(values[start:end], nil, nil)
+									return err
+								})
+							case *file.BooleanColumnChunkWriter:
+								values := columns[c].([]bool)
+								err = writeBatches(len(values), batchSize, func(start, end int) error {
+									_, err := col.WriteBatch(values[start:end], nil, nil)
+									return err
+								})
+							case *file.ByteArrayColumnChunkWriter:
+								values := columns[c].([]parquet3.ByteArray)
+								err = writeBatches(len(values), batchSize, func(start, end int) error {
+									_, err := col.WriteBatch(values[start:end], nil, nil)
+									return err
+								})
+							default:
+								b.Fatalf("unexpected column writer %T", col)
+							}
+							if err != nil {
+								b.Fatalf("WriteBatch failed: %v", err)
+							}
+
+							col.Close()
+						}
+					}()
+				}
+
+				b.StopTimer()
+				verifyWideFile(b, filename, t)
+				reportWideWriting(b, filename, created)
+			})
+		}
+	})
+
+	b.Run("apache_arrow_pqarrow", func(b *testing.B) {
+		filename := prefix + "apache_arrow_pqarrow.parquet"
+
+		columns := make([]interface{}, t.numColumns)
+		for c := range columns {
+			columns[c] = t.column(c)
+		}
+
+		var created time.Duration
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				start := time.Now()
+
+				w, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+				if err != nil {
+					b.Fatalf("Opening file failed: %v", err)
+				}
+				defer w.Close()
+
+				sc := t.pqarrowSchema()
+
+				fw, err := pqarrow.NewFileWriter(sc, w,
+					parquet3.NewWriterProperties(parquet3.WithCompression(compress.Codecs.Snappy)),
+					pqarrow.DefaultWriterProps(),
+				)
+				if err != nil {
+					b.Fatalf("Creating pqarrow writer failed: %v", err)
+				}
+
+				created += time.Since(start)
+
+				bld := array.NewRecordBuilder(memory.DefaultAllocator, sc)
+				defer bld.Release()
+
+				for c, values := range columns {
+					switch fb := bld.Field(c).(type) {
+					case *array.Int32Builder:
+						fb.AppendValues(values.([]int32), nil)
+					case *array.Int64Builder:
+						fb.AppendValues(values.([]int64), nil)
+					case *array.Float64Builder:
+						fb.AppendValues(values.([]float64), nil)
+					case *array.BooleanBuilder:
+						fb.AppendValues(values.([]bool), nil)
+					case *array.StringBuilder:
+						fb.AppendValues(values.([]string), nil)
+					default:
+						b.Fatalf("unexpected field builder %T", fb)
+					}
+				}
+
+				rec := bld.NewRecord()
+				defer rec.Release()
+
+				if err := fw.Write(rec); err != nil {
+					b.Fatalf("Write failed: %v", err)
+				}
+
+				if err := fw.Close(); err != nil {
+					b.Fatalf("Closing pqarrow writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyWideFile(b, filename, t)
+		reportWideWriting(b, filename, created)
+	})
+
+	b.Run("segmentio_parquet_go", func(b *testing.B) {
+		parquetFilename := prefix + "segmentio.parquet"
+
+		var created time.Duration
+
+		for n := 0; n < b.N; n++ {
+			func() {
+				start := time.Now()
+
+				f, err := os.Create(parquetFilename)
+				if err != nil {
+					b.Fatalf("Creating %s failed: %v", parquetFilename, err)
+				}
+
+				wr := parquet4.NewWriter(f, parquet4.SchemaOf(structs[0]), parquet4.Compression(&snappy.Codec{}))
+
+				created += time.Since(start)
+
+				for _, s := range structs {
+					if err := wr.Write(s); err != nil {
+						b.Fatalf("Write failed: %v", err)
+					}
+				}
+
+				if err := wr.Close(); err != nil {
+					b.Fatalf("Closing parquet writer failed: %v", err)
+				}
+			}()
+		}
+
+		b.StopTimer()
+		verifyWideFile(b, parquetFilename, t)
+		reportWideWriting(b, parquetFilename, created)
+	})
+}